@@ -2,6 +2,8 @@
 ((block "{" @open "}" @close))
 ((trait_body "{" @open "}" @close))
 ((object_literal "{" @open "}" @close))
+((match_block "{" @open "}" @close))
+((enum_body "{" @open "}" @close))
 
 ; Prompt delimiters
 ((prompt_body
@@ -15,3 +17,5 @@
 
 ; Square brackets
 ((array_literal "[" @open "]" @close))
+((array_type "[" @open "]" @close))
+((subscript_expression "[" @open "]" @close))
@@ -2,15 +2,19 @@
 [
   "worker"
   "trait"
+  "skill"
   "task"
   "fun"
   "type"
+  "enum"
   "var"
   "if"
   "else"
   "for"
   "in"
   "while"
+  "match"
+  "async"
   "await"
   "return"
   "succeed"
@@ -19,6 +23,7 @@
   "continue"
   "import"
   "from"
+  "as"
   "export"
   "default"
   "think"
@@ -27,31 +32,145 @@
 
 ((prompt_do) @keyword)
 
+; Operators
+[
+  "="
+  "+="
+  "-="
+  "*="
+  "/="
+  "%="
+  "&&="
+  "||="
+  "??="
+  "=="
+  "!="
+  "<"
+  "<="
+  ">"
+  ">="
+  "+"
+  "-"
+  "*"
+  "/"
+  "%"
+  "**"
+  "!"
+  "&&"
+  "||"
+  "&"
+  "|"
+  "^"
+  "<<"
+  ">>"
+  "..."
+  ".."
+  "..="
+  "=>"
+  "?"
+  "??"
+  (optional_chain)
+] @operator
+
+(ternary_expression ":" @operator)
+
 ((annotation name: (identifier) @attribute)
  (#match? @attribute "^@?"))
 
 ((worker_declaration name: (identifier) @type))
 ((trait_declaration name: (identifier) @type))
+((skill_declaration name: (identifier) @function))
 ((task_declaration name: (identifier) @function))
 ((function_declaration name: (identifier) @function))
+((method_signature name: (identifier) @function))
 ((type_declaration name: (identifier) @type))
+((enum_declaration name: (identifier) @type))
+((enum_variant name: (identifier) @constructor))
+((enum_field name: (identifier) @property))
+; Builtin types, only where a type is expected, so a variable or property
+; that shares the name is left alone.
+([
+  (type_parameter bound: (identifier) @type.builtin)
+  (generic_type base: (identifier) @type.builtin)
+  (type_arguments (identifier) @type.builtin)
+  (tuple_type (identifier) @type.builtin)
+  (array_type element: (identifier) @type.builtin)
+  (function_type (identifier) @type.builtin)
+  (type_declaration value: (identifier) @type.builtin)
+  (enum_field type: (identifier) @type.builtin)
+  (var_declaration type: (identifier) @type.builtin)
+  (parameter type: (identifier) @type.builtin)
+  (function_declaration return_type: (identifier) @type.builtin)
+  (method_signature return_type: (identifier) @type.builtin)
+  (lambda_expression return_type: (identifier) @type.builtin)
+ ]
+ (#any-of? @type.builtin
+  "Int" "Float" "Number" "String" "Bool" "Char" "Unit" "Any" "Never"
+  "int" "float" "number" "string" "bool"))
+
+((type_parameter name: (identifier) @type))
+((generic_type base: (identifier) @type))
+((type_arguments (identifier) @type))
+((tuple_type (identifier) @type))
+((array_type element: (identifier) @type))
+((var_declaration type: (identifier) @type))
+((parameter type: (identifier) @type))
+((function_declaration return_type: (identifier) @type))
+((method_signature return_type: (identifier) @type))
+((lambda_expression return_type: (identifier) @type))
+
+((while_statement label: (identifier) @label))
+((for_statement label: (identifier) @label))
+((break_statement label: (identifier) @label))
+((continue_statement label: (identifier) @label))
 
 ((parameter name: (identifier) @variable.parameter))
+((lambda_expression parameters: (identifier) @variable.parameter))
+((named_argument name: (identifier) @variable.parameter))
+
+; Calls
+((call_expression
+  function: (identifier) @function.builtin)
+ (#any-of? @function.builtin
+  "cat" "json" "print" "len" "keys" "values" "typeof" "read" "write"
+  "assert"))
+((call_expression function: (identifier) @function.call))
+((call_expression
+  function: (member_expression property: (identifier) @function.method.call)))
+
+((self_expression) @variable.builtin)
+((wildcard_pattern) @variable.builtin)
+((constructor_pattern constructor: (identifier) @constructor))
 ((identifier) @variable)
 ((exit_status) @variable)
 
 ((object_field key: (identifier) @property))
-((object_field key: (object_key) @property))
+((object_field key: (object_key [(identifier) (string)]) @property))
 
-((number) @number)
-((boolean) @constant)
+((integer) @number)
+((float) @number.float)
+((boolean) @constant.builtin)
 ((string) @string)
+((interpolated_string "\"" @string))
+((string_content) @string)
+((char_literal) @character)
+((escape_sequence) @string.escape)
+((regex "/" @punctuation.bracket))
+((regex_pattern) @string.regexp)
+((regex_flags) @character.special)
+((interpolation "${" @punctuation.special))
+((interpolation "}" @punctuation.special))
+((heredoc_start) @string.special)
+((heredoc_content) @string)
+((heredoc_end) @string.special)
 ((prompt_text) @string.special)
 ((prompt_escape) @string.special)
 ((prompt_interpolation "${" @punctuation.special))
 ((prompt_interpolation "}" @punctuation.special))
 
 ((comment) @comment)
+((block_comment) @comment)
+((doc_comment) @comment.documentation)
 
 ((shell_command_statement "$" @punctuation.special))
 ((shell_command_statement command: (shell_text) @string.special))
@@ -1,3 +1,5 @@
+; Zed reads @indent; Neovim reads the @indent.* captures.
+
 ; Indent inside blocks
 [
   (block)
@@ -5,6 +7,36 @@
   (prompt_block)
   (array_literal)
   (object_literal)
+  (match_block)
+  (enum_body)
   (parameter_list)
   (argument_list)
-] @indent
+] @indent @indent.begin
+
+; Continuation lines of a hanging expression stay indented. Neovim ignores
+; @indent.begin on a node that fits on one line, so this only affects
+; expressions that continue onto another line. Only the outermost operator of
+; a chain counts, so `a **\n b **\n c` indents its continuation lines once
+; rather than once per operator
+((binary_expression) @indent.begin
+  (#not-has-parent? @indent.begin binary_expression))
+
+(match_arm) @indent.begin
+
+; Closers line up with the line that opened them
+[
+  "}"
+  "]"
+  ")"
+] @indent.branch @indent.end
+
+(else_clause
+  "else" @indent.branch)
+
+; Leave the contents of multi-line literals and comments alone
+[
+  (heredoc_content)
+  (prompt_text)
+] @indent.ignore
+
+(block_comment) @indent.auto
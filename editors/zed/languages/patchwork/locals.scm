@@ -0,0 +1,70 @@
+; Scopes
+[
+  (worker_declaration)
+  (skill_declaration)
+  (task_declaration)
+  (function_declaration)
+  (method_signature)
+  (lambda_expression)
+  (block)
+  (for_statement)
+  (if_statement)
+  (while_statement)
+  (match_arm)
+] @local.scope
+
+; Definitions
+(parameter
+  name: (identifier) @local.definition)
+
+(lambda_expression
+  parameters: (identifier) @local.definition)
+
+(var_declaration
+  name: (identifier) @local.definition)
+
+(for_statement
+  iterator: (identifier) @local.definition)
+
+(var_condition
+  pattern: (identifier) @local.definition)
+
+(import_statement
+  clause: (identifier) @local.definition)
+
+(import_specifier
+  name: (identifier) @local.definition
+  !alias)
+
+(import_specifier
+  alias: (identifier) @local.definition)
+
+(namespace_import
+  alias: (identifier) @local.definition)
+
+(match_arm
+  pattern: (identifier) @local.definition)
+
+(tuple_pattern
+  (identifier) @local.definition)
+
+(constructor_pattern
+  argument: (identifier) @local.definition)
+
+(array_pattern
+  (identifier) @local.definition)
+
+(object_pattern
+  (identifier) @local.definition)
+
+(pair_pattern
+  value: (identifier) @local.definition)
+
+(assignment_pattern
+  left: (identifier) @local.definition)
+
+(rest_pattern
+  (identifier) @local.definition)
+
+; References
+(identifier) @local.reference
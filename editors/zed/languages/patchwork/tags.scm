@@ -0,0 +1,43 @@
+; Workers, skills, and tasks are entry points, tagged like functions
+(worker_declaration
+  name: (identifier) @name) @definition.function
+
+(skill_declaration
+  name: (identifier) @name) @definition.function
+
+(task_declaration
+  name: (identifier) @name) @definition.function
+
+; Top-level functions
+(source_file
+  (function_declaration
+    name: (identifier) @name) @definition.function)
+
+(export_statement
+  declaration: (function_declaration
+    name: (identifier) @name) @definition.function)
+
+; Functions declared inside a trait are methods
+(trait_body
+  (function_declaration
+    name: (identifier) @name) @definition.method)
+
+(method_signature
+  name: (identifier) @name) @definition.method
+
+(trait_declaration
+  name: (identifier) @name) @definition.class
+
+(type_declaration
+  name: (identifier) @name) @definition.type
+
+(enum_declaration
+  name: (identifier) @name) @definition.type
+
+; Calls
+(call_expression
+  function: (identifier) @name) @reference.call
+
+(call_expression
+  function: (member_expression
+    property: (identifier) @name)) @reference.call
@@ -34,3 +34,7 @@
 
 ; Comments
 (comment)+ @comment.around
+
+(block_comment) @comment.around
+
+(doc_comment)+ @comment.around
@@ -0,0 +1,22 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
+
+cd "$ROOT/tree-sitter"
+
+# Builds tree-sitter-patchwork.wasm for web-tree-sitter and smoke-tests it.
+# Requires emcc or docker/podman, which the tree-sitter CLI uses to compile.
+# The parser is regenerated first so the WASM always matches grammar.js.
+npx tree-sitter generate
+npx tree-sitter build --wasm -o tree-sitter-patchwork.wasm
+
+# web-tree-sitter is versioned separately from the CLI, so it has its own pin
+# in package.json.
+WEB_TREE_SITTER_VERSION="$(node -p 'require("./package.json").devDependencies["web-tree-sitter"]')"
+INSTALLED_VERSION="$(node -p 'require("./node_modules/web-tree-sitter/package.json").version' 2>/dev/null || true)"
+if [ "$INSTALLED_VERSION" != "$WEB_TREE_SITTER_VERSION" ]; then
+  npm install --no-save "web-tree-sitter@$WEB_TREE_SITTER_VERSION"
+fi
+
+node test/wasm/smoke.mjs tree-sitter-patchwork.wasm
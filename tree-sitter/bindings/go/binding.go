@@ -1,6 +1,9 @@
 package tree_sitter_patchwork
 
-// #cgo CFLAGS: -std=c11 -fPIC
+//go:generate go run gen_node_types.go
+//go:generate go run gen_fields.go
+
+// #cgo CFLAGS: -I../../src -std=c11 -fPIC
 // #include "../../src/parser.c"
 // #include "../../src/scanner.c"
 import "C"
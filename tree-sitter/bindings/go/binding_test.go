@@ -13,3 +13,35 @@ func TestCanLoadGrammar(t *testing.T) {
 		t.Errorf("Error loading Patchwork grammar")
 	}
 }
+
+func TestNodeTypeConstants(t *testing.T) {
+	language := tree_sitter.NewLanguage(tree_sitter_patchwork.Language())
+	root := tree_sitter.Parse([]byte("fun greet(name) {\n    return name\n}\n"), language)
+
+	if root.Type() != tree_sitter_patchwork.NodeTypeSourceFile {
+		t.Errorf("root type = %q, want %q", root.Type(), tree_sitter_patchwork.NodeTypeSourceFile)
+	}
+
+	fun := root.NamedChild(0)
+	if fun.Type() != tree_sitter_patchwork.NodeTypeFunctionDeclaration {
+		t.Errorf("declaration type = %q, want %q", fun.Type(), tree_sitter_patchwork.NodeTypeFunctionDeclaration)
+	}
+
+	body := fun.ChildByFieldName("body")
+	if body.Type() != tree_sitter_patchwork.NodeTypeBlock {
+		t.Errorf("body type = %q, want %q", body.Type(), tree_sitter_patchwork.NodeTypeBlock)
+	}
+}
+
+func TestLanguageVersion(t *testing.T) {
+	oldest := tree_sitter_patchwork.MinCompatibleVersion()
+	newest := tree_sitter_patchwork.MaxCompatibleVersion()
+	if oldest == 0 || oldest > newest {
+		t.Fatalf("runtime reports an empty supported range %d..%d", oldest, newest)
+	}
+
+	version := tree_sitter_patchwork.LanguageVersion()
+	if version < oldest || version > newest {
+		t.Errorf("language version %d is outside the runtime's supported range %d..%d", version, oldest, newest)
+	}
+}
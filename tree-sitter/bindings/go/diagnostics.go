@@ -0,0 +1,62 @@
+package tree_sitter_patchwork
+
+import sitter "github.com/smacker/go-tree-sitter"
+
+// Diagnostic describes an ERROR or MISSING node found in a parse tree.
+type Diagnostic struct {
+	StartByte  uint32
+	EndByte    uint32
+	StartPoint sitter.Point
+	EndPoint   sitter.Point
+
+	// IsError is set for ERROR nodes, which wrap input the parser could not
+	// fit into the grammar.
+	IsError bool
+	// IsMissing is set for zero-width nodes the parser inserted to recover,
+	// such as a closing brace at the end of the file.
+	IsMissing bool
+
+	// Type is the node type. For a MISSING node it names the expected token.
+	Type string
+	// Text is the source covered by the node; empty for MISSING nodes.
+	Text string
+}
+
+// Diagnostics returns every ERROR and MISSING node in tree, in document order.
+// Subtrees without errors are skipped, and the contents of an ERROR node are
+// not reported separately.
+func Diagnostics(tree *sitter.Tree, src []byte) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	root := tree.RootNode()
+	if !root.HasError() {
+		return nil
+	}
+
+	cursor := sitter.NewTreeCursor(root)
+	defer cursor.Close()
+
+	for {
+		node := cursor.CurrentNode()
+		if node.IsError() || node.IsMissing() {
+			diagnostics = append(diagnostics, Diagnostic{
+				StartByte:  node.StartByte(),
+				EndByte:    node.EndByte(),
+				StartPoint: node.StartPoint(),
+				EndPoint:   node.EndPoint(),
+				IsError:    node.IsError(),
+				IsMissing:  node.IsMissing(),
+				Type:       node.Type(),
+				Text:       node.Content(src),
+			})
+		} else if node.HasError() && cursor.GoToFirstChild() {
+			continue
+		}
+
+		for !cursor.GoToNextSibling() {
+			if !cursor.GoToParent() {
+				return diagnostics
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package tree_sitter_patchwork_test
+
+import (
+	"testing"
+
+	tree_sitter "github.com/smacker/go-tree-sitter"
+	"github.com/tree-sitter/tree-sitter-patchwork"
+)
+
+func TestDiagnosticsValidInput(t *testing.T) {
+	src := []byte("fun greet(name) {\n    return name\n}\n")
+	if diagnostics := tree_sitter_patchwork.Diagnostics(mustParse(t, src), src); len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, got %+v", diagnostics)
+	}
+}
+
+func TestDiagnosticsMissingClosingBrace(t *testing.T) {
+	src := []byte("fun greet(name) {\n    return name\n")
+	diagnostics := tree_sitter_patchwork.Diagnostics(mustParse(t, src), src)
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %+v", diagnostics)
+	}
+
+	d := diagnostics[0]
+	if !d.IsMissing || d.IsError {
+		t.Errorf("expected a MISSING node, got %+v", d)
+	}
+	if d.Type != "}" {
+		t.Errorf("missing node type = %q, want %q", d.Type, "}")
+	}
+	if d.StartByte != uint32(len(src)) || d.EndByte != uint32(len(src)) {
+		t.Errorf("missing node range = %d..%d, want %d..%d", d.StartByte, d.EndByte, len(src), len(src))
+	}
+	if d.StartPoint != (tree_sitter.Point{Row: 2, Column: 0}) {
+		t.Errorf("missing node start = %+v, want 2:0", d.StartPoint)
+	}
+}
+
+func TestDiagnosticsStrayToken(t *testing.T) {
+	src := []byte("var x = 1 )\nvar y = 2\n")
+	diagnostics := tree_sitter_patchwork.Diagnostics(mustParse(t, src), src)
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %+v", diagnostics)
+	}
+
+	d := diagnostics[0]
+	if !d.IsError || d.IsMissing {
+		t.Errorf("expected an ERROR node, got %+v", d)
+	}
+	if d.StartByte != 10 || d.EndByte != 11 {
+		t.Errorf("error range = %d..%d, want 10..11", d.StartByte, d.EndByte)
+	}
+	if d.StartPoint != (tree_sitter.Point{Row: 0, Column: 10}) {
+		t.Errorf("error start = %+v, want 0:10", d.StartPoint)
+	}
+	if d.Text != ")" {
+		t.Errorf("error text = %q, want %q", d.Text, ")")
+	}
+}
@@ -0,0 +1,14 @@
+package tree_sitter_patchwork
+
+import sitter "github.com/smacker/go-tree-sitter"
+
+// FieldText returns the source text of the child of n stored under field,
+// such as FieldName on a function declaration. It reports false if n has no
+// child for that field.
+func FieldText(n *sitter.Node, field string, src []byte) (string, bool) {
+	child := n.ChildByFieldName(field)
+	if child == nil {
+		return "", false
+	}
+	return child.Content(src), true
+}
@@ -0,0 +1,22 @@
+package tree_sitter_patchwork_test
+
+import (
+	"testing"
+
+	"github.com/tree-sitter/tree-sitter-patchwork"
+)
+
+func TestFieldText(t *testing.T) {
+	src := []byte("fun greet(name, greeting) {\n    return name\n}\n")
+	fun := mustParse(t, src).RootNode().NamedChild(0)
+
+	if name, ok := tree_sitter_patchwork.FieldText(fun, tree_sitter_patchwork.FieldName, src); !ok || name != "greet" {
+		t.Errorf("name = %q, %v; want %q, true", name, ok, "greet")
+	}
+	if params, ok := tree_sitter_patchwork.FieldText(fun, tree_sitter_patchwork.FieldParameters, src); !ok || params != "(name, greeting)" {
+		t.Errorf("parameters = %q, %v; want %q, true", params, ok, "(name, greeting)")
+	}
+	if _, ok := tree_sitter_patchwork.FieldText(fun, tree_sitter_patchwork.FieldCondition, src); ok {
+		t.Errorf("expected no condition field on a function declaration")
+	}
+}
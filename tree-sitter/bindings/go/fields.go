@@ -0,0 +1,51 @@
+// Code generated by gen_fields.go; DO NOT EDIT.
+
+package tree_sitter_patchwork
+
+// Field names used by the Patchwork grammar, for use with Node.ChildByFieldName.
+const (
+	FieldAlias          = "alias"
+	FieldAlternative    = "alternative"
+	FieldArgument       = "argument"
+	FieldArguments      = "arguments"
+	FieldBase           = "base"
+	FieldBody           = "body"
+	FieldBound          = "bound"
+	FieldClause         = "clause"
+	FieldCommand        = "command"
+	FieldCondition      = "condition"
+	FieldConsequence    = "consequence"
+	FieldConstructor    = "constructor"
+	FieldDeclaration    = "declaration"
+	FieldElement        = "element"
+	FieldEnd            = "end"
+	FieldExpression     = "expression"
+	FieldFlags          = "flags"
+	FieldFunction       = "function"
+	FieldGuard          = "guard"
+	FieldIdentifier     = "identifier"
+	FieldIndex          = "index"
+	FieldInitializer    = "initializer"
+	FieldIterable       = "iterable"
+	FieldIterator       = "iterator"
+	FieldKey            = "key"
+	FieldKind           = "kind"
+	FieldLabel          = "label"
+	FieldLeft           = "left"
+	FieldName           = "name"
+	FieldObject         = "object"
+	FieldOperator       = "operator"
+	FieldOptional       = "optional"
+	FieldParameters     = "parameters"
+	FieldPattern        = "pattern"
+	FieldPayload        = "payload"
+	FieldProperty       = "property"
+	FieldReturnType     = "return_type"
+	FieldRight          = "right"
+	FieldSource         = "source"
+	FieldStart          = "start"
+	FieldType           = "type"
+	FieldTypeArguments  = "type_arguments"
+	FieldTypeParameters = "type_parameters"
+	FieldValue          = "value"
+)
@@ -0,0 +1,75 @@
+//go:build ignore
+
+// gen_fields reads the generated node-types.json and writes fields.go, which
+// declares a string constant for every field name used by the grammar.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+type nodeType struct {
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+func main() {
+	data, err := os.ReadFile("../../src/node-types.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var nodes []nodeType
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		log.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	for _, n := range nodes {
+		for field := range n.Fields {
+			if seen[field] {
+				continue
+			}
+			seen[field] = true
+			names = append(names, field)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_fields.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package tree_sitter_patchwork\n\n")
+	buf.WriteString("// Field names used by the Patchwork grammar, for use with Node.ChildByFieldName.\n")
+	buf.WriteString("const (\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "\tField%s = %q\n", camelCase(name), name)
+	}
+	buf.WriteString(")\n")
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("fields.go", out, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func camelCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+//go:build ignore
+
+// gen_node_types reads the generated node-types.json and writes node_types.go,
+// which declares a string constant for every named node kind in the grammar.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+type nodeType struct {
+	Type  string `json:"type"`
+	Named bool   `json:"named"`
+}
+
+func main() {
+	data, err := os.ReadFile("../../src/node-types.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var nodes []nodeType
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		log.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	for _, n := range nodes {
+		if !n.Named || seen[n.Type] {
+			continue
+		}
+		seen[n.Type] = true
+		names = append(names, n.Type)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_node_types.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package tree_sitter_patchwork\n\n")
+	buf.WriteString("// Named node kinds produced by the Patchwork grammar, as reported by Node.Type().\n")
+	buf.WriteString("const (\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "\tNodeType%s = %q\n", camelCase(name), name)
+	}
+	buf.WriteString(")\n")
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("node_types.go", out, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func camelCase(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]))
+		b.WriteString(part[1:])
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package tree_sitter_patchwork_test
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"testing"
+)
+
+type nodeTypeEntry struct {
+	Type   string                     `json:"type"`
+	Named  bool                       `json:"named"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+func loadNodeTypes(t *testing.T) []nodeTypeEntry {
+	t.Helper()
+	data, err := os.ReadFile("../../src/node-types.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nodes []nodeTypeEntry
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		t.Fatal(err)
+	}
+	return nodes
+}
+
+var constantPattern = regexp.MustCompile(`(?m)^\s*\w+\s*=\s*"([^"]*)"\s*$`)
+
+// generatedConstants returns the string values of the constants declared in a
+// generated source file.
+func generatedConstants(t *testing.T, name string) []string {
+	t.Helper()
+	src, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var values []string
+	for _, m := range constantPattern.FindAllStringSubmatch(string(src), -1) {
+		values = append(values, m[1])
+	}
+	sort.Strings(values)
+	return values
+}
+
+func checkGenerated(t *testing.T, name string, got, want []string) {
+	t.Helper()
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Errorf("%s declares %d constants, node-types.json has %d; run go generate", name, len(got), len(want))
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("%s declares %q where node-types.json has %q; run go generate", name, got[i], want[i])
+			return
+		}
+	}
+}
+
+// node_types.go and fields.go are generated from node-types.json, so they go
+// stale whenever the parser is regenerated without running `go generate`.
+func TestNodeTypesUpToDate(t *testing.T) {
+	seen := map[string]bool{}
+	var want []string
+	for _, n := range loadNodeTypes(t) {
+		if n.Named && !seen[n.Type] {
+			seen[n.Type] = true
+			want = append(want, n.Type)
+		}
+	}
+	checkGenerated(t, "node_types.go", generatedConstants(t, "node_types.go"), want)
+}
+
+func TestFieldsUpToDate(t *testing.T) {
+	seen := map[string]bool{}
+	var want []string
+	for _, n := range loadNodeTypes(t) {
+		for field := range n.Fields {
+			if !seen[field] {
+				seen[field] = true
+				want = append(want, field)
+			}
+		}
+	}
+	checkGenerated(t, "fields.go", generatedConstants(t, "fields.go"), want)
+}
@@ -0,0 +1,124 @@
+// Code generated by gen_node_types.go; DO NOT EDIT.
+
+package tree_sitter_patchwork
+
+// Named node kinds produced by the Patchwork grammar, as reported by Node.Type().
+const (
+	NodeTypeAnnotation                    = "annotation"
+	NodeTypeArgumentList                  = "argument_list"
+	NodeTypeArrayLiteral                  = "array_literal"
+	NodeTypeArrayPattern                  = "array_pattern"
+	NodeTypeArrayType                     = "array_type"
+	NodeTypeAssignmentExpression          = "assignment_expression"
+	NodeTypeAssignmentPattern             = "assignment_pattern"
+	NodeTypeAugmentedAssignmentExpression = "augmented_assignment_expression"
+	NodeTypeAwaitExpression               = "await_expression"
+	NodeTypeBinaryExpression              = "binary_expression"
+	NodeTypeBlock                         = "block"
+	NodeTypeBlockComment                  = "block_comment"
+	NodeTypeBoolean                       = "boolean"
+	NodeTypeBreakStatement                = "break_statement"
+	NodeTypeCallExpression                = "call_expression"
+	NodeTypeCharLiteral                   = "char_literal"
+	NodeTypeComment                       = "comment"
+	NodeTypeComputedKey                   = "computed_key"
+	NodeTypeConditionChain                = "condition_chain"
+	NodeTypeConstructorPattern            = "constructor_pattern"
+	NodeTypeContinueStatement             = "continue_statement"
+	NodeTypeDocComment                    = "doc_comment"
+	NodeTypeElseClause                    = "else_clause"
+	NodeTypeEnumBody                      = "enum_body"
+	NodeTypeEnumDeclaration               = "enum_declaration"
+	NodeTypeEnumField                     = "enum_field"
+	NodeTypeEnumRecordPayload             = "enum_record_payload"
+	NodeTypeEnumTuplePayload              = "enum_tuple_payload"
+	NodeTypeEnumVariant                   = "enum_variant"
+	NodeTypeEscapeSequence                = "escape_sequence"
+	NodeTypeExitStatus                    = "exit_status"
+	NodeTypeExportList                    = "export_list"
+	NodeTypeExportSpecifier               = "export_specifier"
+	NodeTypeExportStatement               = "export_statement"
+	NodeTypeExpression                    = "expression"
+	NodeTypeExpressionStatement           = "expression_statement"
+	NodeTypeFloat                         = "float"
+	NodeTypeForStatement                  = "for_statement"
+	NodeTypeFunctionDeclaration           = "function_declaration"
+	NodeTypeFunctionType                  = "function_type"
+	NodeTypeGenericType                   = "generic_type"
+	NodeTypeHeredoc                       = "heredoc"
+	NodeTypeHeredocBody                   = "heredoc_body"
+	NodeTypeHeredocContent                = "heredoc_content"
+	NodeTypeHeredocEnd                    = "heredoc_end"
+	NodeTypeHeredocStart                  = "heredoc_start"
+	NodeTypeIdentifier                    = "identifier"
+	NodeTypeIfStatement                   = "if_statement"
+	NodeTypeImportList                    = "import_list"
+	NodeTypeImportSpecifier               = "import_specifier"
+	NodeTypeImportStatement               = "import_statement"
+	NodeTypeInteger                       = "integer"
+	NodeTypeInterpolatedString            = "interpolated_string"
+	NodeTypeInterpolation                 = "interpolation"
+	NodeTypeLambdaExpression              = "lambda_expression"
+	NodeTypeMatchArm                      = "match_arm"
+	NodeTypeMatchBlock                    = "match_block"
+	NodeTypeMatchExpression               = "match_expression"
+	NodeTypeMemberExpression              = "member_expression"
+	NodeTypeMethodSignature               = "method_signature"
+	NodeTypeNamedArgument                 = "named_argument"
+	NodeTypeNamespaceImport               = "namespace_import"
+	NodeTypeObjectField                   = "object_field"
+	NodeTypeObjectKey                     = "object_key"
+	NodeTypeObjectLiteral                 = "object_literal"
+	NodeTypeObjectPattern                 = "object_pattern"
+	NodeTypeOptionalChain                 = "optional_chain"
+	NodeTypePairPattern                   = "pair_pattern"
+	NodeTypeParameter                     = "parameter"
+	NodeTypeParameterList                 = "parameter_list"
+	NodeTypeParenthesizedExpression       = "parenthesized_expression"
+	NodeTypePattern                       = "pattern"
+	NodeTypePromptBlock                   = "prompt_block"
+	NodeTypePromptBody                    = "prompt_body"
+	NodeTypePromptDo                      = "prompt_do"
+	NodeTypePromptDoBlock                 = "prompt_do_block"
+	NodeTypePromptEnd                     = "prompt_end"
+	NodeTypePromptEscape                  = "prompt_escape"
+	NodeTypePromptInterpolation           = "prompt_interpolation"
+	NodeTypePromptStart                   = "prompt_start"
+	NodeTypePromptText                    = "prompt_text"
+	NodeTypeRangeExpression               = "range_expression"
+	NodeTypeRegex                         = "regex"
+	NodeTypeRegexFlags                    = "regex_flags"
+	NodeTypeRegexPattern                  = "regex_pattern"
+	NodeTypeRestPattern                   = "rest_pattern"
+	NodeTypeReturnStatement               = "return_statement"
+	NodeTypeSelfExpression                = "self_expression"
+	NodeTypeShellCommandExpression        = "shell_command_expression"
+	NodeTypeShellCommandStatement         = "shell_command_statement"
+	NodeTypeShellInnerText                = "shell_inner_text"
+	NodeTypeShellText                     = "shell_text"
+	NodeTypeSkillDeclaration              = "skill_declaration"
+	NodeTypeSourceFile                    = "source_file"
+	NodeTypeSpreadElement                 = "spread_element"
+	NodeTypeStatement                     = "statement"
+	NodeTypeString                        = "string"
+	NodeTypeStringContent                 = "string_content"
+	NodeTypeSubscriptExpression           = "subscript_expression"
+	NodeTypeTaskDeclaration               = "task_declaration"
+	NodeTypeTernaryExpression             = "ternary_expression"
+	NodeTypeTraitBody                     = "trait_body"
+	NodeTypeTraitDeclaration              = "trait_declaration"
+	NodeTypeTupleLiteral                  = "tuple_literal"
+	NodeTypeTuplePattern                  = "tuple_pattern"
+	NodeTypeTupleType                     = "tuple_type"
+	NodeTypeTypeArguments                 = "type_arguments"
+	NodeTypeTypeDeclaration               = "type_declaration"
+	NodeTypeTypeExpression                = "type_expression"
+	NodeTypeTypeParameter                 = "type_parameter"
+	NodeTypeTypeParameters                = "type_parameters"
+	NodeTypeUnaryExpression               = "unary_expression"
+	NodeTypeVarCondition                  = "var_condition"
+	NodeTypeVarDeclaration                = "var_declaration"
+	NodeTypeWhileStatement                = "while_statement"
+	NodeTypeWildcardPattern               = "wildcard_pattern"
+	NodeTypeWorkerDeclaration             = "worker_declaration"
+)
@@ -0,0 +1,24 @@
+package tree_sitter_patchwork
+
+import (
+	"context"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Parse parses src with a parser configured for Patchwork.
+func Parse(src []byte) (*sitter.Tree, error) {
+	return ParseCtx(context.Background(), src, nil)
+}
+
+// ParseCtx parses src with a parser configured for Patchwork, stopping early
+// if ctx is canceled. When old is non-nil it must already reflect any edits
+// made to the source, and unchanged subtrees are reused from it.
+//
+// If parsing is canceled, ParseCtx returns a nil tree and ctx.Err().
+func ParseCtx(ctx context.Context, src []byte, old *sitter.Tree) (*sitter.Tree, error) {
+	parser := sitter.NewParser()
+	defer parser.Close()
+	parser.SetLanguage(sitter.NewLanguage(Language()))
+	return parser.ParseCtx(ctx, old, src)
+}
@@ -0,0 +1,79 @@
+package tree_sitter_patchwork_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	tree_sitter "github.com/smacker/go-tree-sitter"
+	"github.com/tree-sitter/tree-sitter-patchwork"
+)
+
+func TestParse(t *testing.T) {
+	tree, err := tree_sitter_patchwork.Parse([]byte("fun greet(name) {\n    return name\n}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root := tree.RootNode(); root.Type() != "source_file" || root.HasError() {
+		t.Errorf("unexpected tree: %s", root.String())
+	}
+}
+
+func TestParseCtxReusesOldTree(t *testing.T) {
+	src := []byte("fun greet(name) {\n    return name\n}\n")
+	old, err := tree_sitter_patchwork.Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rename "name" to "person" in the return statement.
+	start := uint32(bytes.LastIndex(src, []byte("name")))
+	edited := append(append(append([]byte{}, src[:start]...), "person"...), src[start+4:]...)
+	old.Edit(tree_sitter.EditInput{
+		StartIndex:  start,
+		OldEndIndex: start + 4,
+		NewEndIndex: start + 6,
+		StartPoint:  tree_sitter.Point{Row: 1, Column: 11},
+		OldEndPoint: tree_sitter.Point{Row: 1, Column: 15},
+		NewEndPoint: tree_sitter.Point{Row: 1, Column: 17},
+	})
+
+	tree, err := tree_sitter_patchwork.ParseCtx(context.Background(), edited, old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tree.RootNode().String(), mustParse(t, edited).RootNode().String(); got != want {
+		t.Errorf("incremental parse differs from a fresh parse:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestParseCtxCanceled(t *testing.T) {
+	var src bytes.Buffer
+	for i := 0; i < 200000; i++ {
+		fmt.Fprintf(&src, "fun f%d(a, b) {\n    return a + b * %d\n}\n", i, i)
+	}
+
+	// The parser notices the cancellation on its next periodic check, long
+	// before it could finish a source this size.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tree, err := tree_sitter_patchwork.ParseCtx(ctx, src.Bytes(), nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if tree != nil {
+		t.Errorf("expected no tree after cancellation")
+	}
+}
+
+func mustParse(t *testing.T, src []byte) *tree_sitter.Tree {
+	t.Helper()
+	tree, err := tree_sitter_patchwork.Parse(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
@@ -0,0 +1,219 @@
+package tree_sitter_patchwork_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	tree_sitter "github.com/smacker/go-tree-sitter"
+	"github.com/tree-sitter/tree-sitter-patchwork"
+)
+
+const queriesDir = "../../queries"
+
+func loadQuery(t *testing.T, name string) *tree_sitter.Query {
+	t.Helper()
+	src, err := os.ReadFile(filepath.Join(queriesDir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, err := tree_sitter.NewQuery(src, tree_sitter.NewLanguage(tree_sitter_patchwork.Language()))
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return query
+}
+
+// Every query must compile, which catches patterns naming node kinds or
+// fields the parser does not have.
+func TestQueriesCompile(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join(queriesDir, "*.scm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no queries found in %s", queriesDir)
+	}
+	for _, path := range paths {
+		name := filepath.Base(path)
+		t.Run(name, func(t *testing.T) {
+			loadQuery(t, name).Close()
+		})
+	}
+}
+
+func TestFolds(t *testing.T) {
+	src := []byte(`/* first
+   second */
+fun greet(name) {
+    log(name)
+}
+
+fun pair(a, b) { return [a, b] }
+
+var items = [
+    1,
+    2
+]
+`)
+	query := loadQuery(t, "folds.scm")
+	defer query.Close()
+
+	cursor := tree_sitter.NewQueryCursor()
+	defer cursor.Close()
+	cursor.Exec(query, mustParse(t, src).RootNode())
+
+	// The query captures single-line constructs too, since editors only fold
+	// the captures that span lines. Each fold ends on the line of its closing
+	// delimiter.
+	var got []string
+	for {
+		match, ok := cursor.NextMatch()
+		if !ok {
+			break
+		}
+		for _, capture := range match.Captures {
+			n := capture.Node
+			got = append(got, fmt.Sprintf("%s %d-%d", n.Type(), n.StartPoint().Row, n.EndPoint().Row))
+		}
+	}
+
+	want := []string{
+		"block_comment 0-1",
+		"function_declaration 2-4",
+		"parameter_list 2-2",
+		"block 2-4",
+		"argument_list 3-3",
+		"function_declaration 6-6",
+		"parameter_list 6-6",
+		"block 6-6",
+		"array_literal 6-6",
+		"array_literal 8-11",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("folds = %q; want %q", got, want)
+	}
+}
+
+type nodeKey struct {
+	start, end uint32
+	symbol     tree_sitter.Symbol
+}
+
+func keyOf(n *tree_sitter.Node) nodeKey {
+	return nodeKey{n.StartByte(), n.EndByte(), n.Symbol()}
+}
+
+// nodeAtPoint returns the smallest node, named or not, that starts at or
+// covers p.
+func nodeAtPoint(n *tree_sitter.Node, p tree_sitter.Point) *tree_sitter.Node {
+	for i := 0; i < int(n.ChildCount()); i++ {
+		child := n.Child(i)
+		start, end := child.StartPoint(), child.EndPoint()
+		startsBefore := start.Row < p.Row || (start.Row == p.Row && start.Column <= p.Column)
+		endsAfter := end.Row > p.Row || (end.Row == p.Row && end.Column > p.Column)
+		if startsBefore && endsAfter {
+			return nodeAtPoint(child, p)
+		}
+	}
+	return n
+}
+
+// hasParentPredicatesHold evaluates the #has-parent? and #not-has-parent?
+// predicates Neovim provides, which the query cursor leaves to its caller.
+func hasParentPredicatesHold(query *tree_sitter.Query, match *tree_sitter.QueryMatch) bool {
+	for _, steps := range query.PredicatesForPattern(uint32(match.PatternIndex)) {
+		operator := query.StringValueForId(steps[0].ValueId)
+		if operator != "has-parent?" && operator != "not-has-parent?" {
+			continue
+		}
+		types := map[string]bool{}
+		for _, step := range steps[2 : len(steps)-1] {
+			types[query.StringValueForId(step.ValueId)] = true
+		}
+		for _, capture := range match.Captures {
+			if capture.Index != steps[1].ValueId {
+				continue
+			}
+			parent := capture.Node.Parent()
+			found := parent != nil && types[parent.Type()]
+			if found != (operator == "has-parent?") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// TestIndents reindents test/indent/sample.pw the way Neovim's indent module
+// reads the captures and expects the file's own indentation back. A
+// continuation line adds one level, and so does each line that opened an
+// indented node still open on a later line. A one-line block adds nothing.
+func TestIndents(t *testing.T) {
+	src, err := os.ReadFile("../../test/indent/sample.pw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := loadQuery(t, "indents.scm")
+	defer query.Close()
+	tree := mustParse(t, src)
+	root := tree.RootNode()
+
+	captures := map[string]map[nodeKey]bool{}
+	cursor := tree_sitter.NewQueryCursor()
+	defer cursor.Close()
+	cursor.Exec(query, root)
+	for {
+		match, ok := cursor.NextMatch()
+		if !ok {
+			break
+		}
+		if !hasParentPredicatesHold(query, match) {
+			continue
+		}
+		for _, capture := range match.Captures {
+			name := query.CaptureNameForId(capture.Index)
+			if captures[name] == nil {
+				captures[name] = map[nodeKey]bool{}
+			}
+			captures[name][keyOf(capture.Node)] = true
+		}
+	}
+
+	for row, line := range bytes.Split(src, []byte("\n")) {
+		text := strings.TrimLeft(string(line), " ")
+		if text == "" {
+			continue
+		}
+		want := (len(line) - len(text)) / 4
+		column := uint32(len(line) - len(text))
+
+		got := 0
+		processed := map[uint32]bool{}
+		lineRow := uint32(row)
+		for n := nodeAtPoint(root, tree_sitter.Point{Row: lineRow, Column: column}); n != nil; n = n.Parent() {
+			key := keyOf(n)
+			startRow, endRow := n.StartPoint().Row, n.EndPoint().Row
+			done := false
+			if !processed[startRow] &&
+				((captures["indent.branch"][key] && startRow == lineRow) ||
+					(captures["indent.dedent"][key] && startRow != lineRow)) {
+				got--
+				done = true
+			}
+			if !processed[startRow] && captures["indent.begin"][key] &&
+				startRow != endRow && startRow != lineRow {
+				got++
+				done = true
+			}
+			processed[startRow] = processed[startRow] || done
+		}
+		if got != want {
+			t.Errorf("line %d %q: indent level %d; want %d", row+1, text, got, want)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package tree_sitter_patchwork
+
+import (
+	"strconv"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// SExprOptions controls how SExprWithOptions renders a tree.
+type SExprOptions struct {
+	// Anonymous includes anonymous nodes such as punctuation and keywords,
+	// rendered as quoted strings.
+	Anonymous bool
+	// LeafText, when positive, appends the source text of nodes without named
+	// children, truncated to that many characters.
+	LeafText int
+}
+
+// SExpr renders n and its named descendants as an indented S-expression with
+// field names, in the same layout as `tree-sitter parse`. MISSING nodes are
+// always included.
+func SExpr(n *sitter.Node, src []byte) string {
+	return SExprWithOptions(n, src, SExprOptions{})
+}
+
+// SExprWithOptions is like SExpr but lets callers include anonymous nodes and
+// leaf text.
+func SExprWithOptions(n *sitter.Node, src []byte, opts SExprOptions) string {
+	cursor := sitter.NewTreeCursor(n)
+	defer cursor.Close()
+
+	var b strings.Builder
+	writeSExpr(&b, cursor, src, opts, 0)
+	return b.String()
+}
+
+func writeSExpr(b *strings.Builder, cursor *sitter.TreeCursor, src []byte, opts SExprOptions, depth int) {
+	node := cursor.CurrentNode()
+	if field := cursor.CurrentFieldName(); field != "" {
+		b.WriteString(field)
+		b.WriteString(": ")
+	}
+
+	switch {
+	case node.IsMissing():
+		b.WriteString("(MISSING ")
+		b.WriteString(nodeLabel(node))
+		b.WriteString(")")
+		return
+	case !node.IsNamed():
+		b.WriteString(strconv.Quote(node.Type()))
+		return
+	}
+
+	b.WriteString("(")
+	b.WriteString(node.Type())
+
+	if cursor.GoToFirstChild() {
+		for {
+			if child := cursor.CurrentNode(); child.IsNamed() || child.IsMissing() || opts.Anonymous {
+				b.WriteString("\n")
+				b.WriteString(strings.Repeat("  ", depth+1))
+				writeSExpr(b, cursor, src, opts, depth+1)
+			}
+			if !cursor.GoToNextSibling() {
+				break
+			}
+		}
+		cursor.GoToParent()
+	}
+
+	if opts.LeafText > 0 && node.NamedChildCount() == 0 {
+		b.WriteString(" ")
+		b.WriteString(strconv.Quote(truncate(node.Content(src), opts.LeafText)))
+	}
+	b.WriteString(")")
+}
+
+func nodeLabel(node *sitter.Node) string {
+	if node.IsNamed() {
+		return node.Type()
+	}
+	return strconv.Quote(node.Type())
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "…"
+}
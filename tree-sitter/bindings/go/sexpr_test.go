@@ -0,0 +1,59 @@
+package tree_sitter_patchwork_test
+
+import (
+	"testing"
+
+	"github.com/tree-sitter/tree-sitter-patchwork"
+)
+
+func TestSExpr(t *testing.T) {
+	src := []byte("fun greet(name) {\n    return name\n}\n")
+	got := tree_sitter_patchwork.SExpr(mustParse(t, src).RootNode(), src)
+	want := `(source_file
+  (function_declaration
+    name: (identifier)
+    parameters: (parameter_list
+      (parameter
+        name: (identifier)))
+    body: (block
+      (return_statement
+        (identifier)))))`
+	if got != want {
+		t.Errorf("SExpr mismatch:\n got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSExprWithOptions(t *testing.T) {
+	src := []byte("fun greeting(name) {\n    return name\n}\n")
+	fun := mustParse(t, src).RootNode().NamedChild(0)
+	got := tree_sitter_patchwork.SExprWithOptions(fun.ChildByFieldName("parameters"), src, tree_sitter_patchwork.SExprOptions{
+		Anonymous: true,
+		LeafText:  3,
+	})
+	want := `(parameter_list
+  "("
+  (parameter
+    name: (identifier "nam…"))
+  ")")`
+	if got != want {
+		t.Errorf("SExprWithOptions mismatch:\n got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSExprMissing(t *testing.T) {
+	src := []byte("fun greet(name) {\n    return name\n")
+	got := tree_sitter_patchwork.SExpr(mustParse(t, src).RootNode(), src)
+	want := `(source_file
+  (function_declaration
+    name: (identifier)
+    parameters: (parameter_list
+      (parameter
+        name: (identifier)))
+    body: (block
+      (return_statement
+        (identifier))
+      (MISSING "}"))))`
+	if got != want {
+		t.Errorf("SExpr mismatch:\n got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package tree_sitter_patchwork
+
+// #include <stdbool.h>
+// #include "../../src/tree_sitter/parser.h"
+//
+// typedef struct TSParser TSParser;
+//
+// TSParser *ts_parser_new(void);
+// void ts_parser_delete(TSParser *);
+// bool ts_parser_set_language(TSParser *, const TSLanguage *);
+// uint32_t ts_language_version(const TSLanguage *);
+// const TSLanguage *tree_sitter_patchwork(void);
+//
+// // The runtime does not export its supported ABI range, so ask it: a copy of
+// // the language that differs only in its version is accepted exactly when that
+// // version is in range. Returns 0 if no version is accepted.
+// static uint32_t patchwork_compatible_version(bool newest) {
+//   TSParser *parser = ts_parser_new();
+//   TSLanguage probe = *tree_sitter_patchwork();
+//   uint32_t found = 0;
+//   for (uint32_t version = 1; version < 64; version++) {
+//     probe.abi_version = version;
+//     if (ts_parser_set_language(parser, &probe)) {
+//       found = version;
+//       if (!newest) {
+//         break;
+//       }
+//     }
+//   }
+//   ts_parser_delete(parser);
+//   return found;
+// }
+import "C"
+
+// LanguageVersion returns the ABI version the Patchwork parser was generated
+// with, as reported by the linked tree-sitter runtime.
+func LanguageVersion() uint32 {
+	return uint32(C.ts_language_version((*C.TSLanguage)(Language())))
+}
+
+// MinCompatibleVersion returns the oldest language ABI version supported by
+// the linked tree-sitter runtime. A LanguageVersion below it means the grammar
+// must be regenerated before it can be used.
+func MinCompatibleVersion() uint32 {
+	return uint32(C.patchwork_compatible_version(false))
+}
+
+// MaxCompatibleVersion returns the newest language ABI version supported by
+// the linked tree-sitter runtime. A LanguageVersion above it means the runtime
+// must be upgraded before the grammar can be used.
+func MaxCompatibleVersion() uint32 {
+	return uint32(C.patchwork_compatible_version(true))
+}
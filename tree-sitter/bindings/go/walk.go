@@ -0,0 +1,45 @@
+package tree_sitter_patchwork
+
+import sitter "github.com/smacker/go-tree-sitter"
+
+// Walk visits root and its descendants depth-first in pre-order, including
+// anonymous nodes, passing each node's depth below root. Children of a node
+// are visited only if fn returns true for it, so returning false prunes that
+// subtree.
+func Walk(root *sitter.Node, fn func(n *sitter.Node, depth int) bool) {
+	cursor := sitter.NewTreeCursor(root)
+	defer cursor.Close()
+
+	depth := 0
+	for {
+		if fn(cursor.CurrentNode(), depth) && cursor.GoToFirstChild() {
+			depth++
+			continue
+		}
+
+		for !cursor.GoToNextSibling() {
+			if depth == 0 || !cursor.GoToParent() {
+				return
+			}
+			depth--
+		}
+	}
+}
+
+// Find returns the first node under root, in the order Walk visits them,
+// for which predicate returns true, or nil if there is none. root itself is
+// a candidate.
+func Find(root *sitter.Node, predicate func(*sitter.Node) bool) *sitter.Node {
+	var found *sitter.Node
+	Walk(root, func(n *sitter.Node, _ int) bool {
+		if found != nil {
+			return false
+		}
+		if predicate(n) {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
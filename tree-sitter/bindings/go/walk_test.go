@@ -0,0 +1,88 @@
+package tree_sitter_patchwork_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/tree-sitter/tree-sitter-patchwork"
+)
+
+func TestWalkOrder(t *testing.T) {
+	src := []byte("fun greet(name) {\n    return name\n}\n")
+	root := mustParse(t, src).RootNode()
+
+	var visited []string
+	tree_sitter_patchwork.Walk(root, func(n *sitter.Node, depth int) bool {
+		if n.IsNamed() {
+			visited = append(visited, fmt.Sprintf("%d:%s", depth, n.Type()))
+		}
+		return true
+	})
+
+	got := strings.Join(visited, " ")
+	want := "0:source_file 1:function_declaration 2:identifier 2:parameter_list 3:parameter 4:identifier 2:block 3:return_statement 4:identifier"
+	if got != want {
+		t.Errorf("visit order:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestWalkPrunes(t *testing.T) {
+	src := []byte("fun greet(name) {\n    return name\n}\nvar x = y\n")
+	root := mustParse(t, src).RootNode()
+
+	var visited []string
+	tree_sitter_patchwork.Walk(root, func(n *sitter.Node, depth int) bool {
+		if n.IsNamed() {
+			visited = append(visited, n.Type())
+		}
+		return n.Type() != "function_declaration"
+	})
+
+	got := strings.Join(visited, " ")
+	want := "source_file function_declaration var_declaration identifier identifier"
+	if got != want {
+		t.Errorf("visit order:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestWalkSubtree(t *testing.T) {
+	src := []byte("fun greet(name) {\n    return name\n}\nvar x = y\n")
+	fun := mustParse(t, src).RootNode().NamedChild(0)
+
+	var types []string
+	tree_sitter_patchwork.Walk(fun.ChildByFieldName("body"), func(n *sitter.Node, depth int) bool {
+		if n.IsNamed() {
+			types = append(types, n.Type())
+		}
+		return true
+	})
+
+	if got, want := strings.Join(types, " "), "block return_statement identifier"; got != want {
+		t.Errorf("visit order = %q; want %q", got, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	src := []byte("var a = 1\nfun greet(name) {\n    return name\n}\n")
+	root := mustParse(t, src).RootNode()
+
+	ret := tree_sitter_patchwork.Find(root, func(n *sitter.Node) bool {
+		return n.Type() == "return_statement"
+	})
+	if ret == nil || ret.Content(src) != "return name" {
+		t.Fatalf("Find(return_statement) = %v", ret)
+	}
+
+	ident := tree_sitter_patchwork.Find(root, func(n *sitter.Node) bool {
+		return n.Type() == "identifier"
+	})
+	if ident == nil || ident.Content(src) != "a" {
+		t.Errorf("first identifier = %v; want a", ident)
+	}
+
+	if n := tree_sitter_patchwork.Find(root, func(n *sitter.Node) bool { return n.Type() == "match_expression" }); n != nil {
+		t.Errorf("Find(match_expression) = %v; want nil", n.Type())
+	}
+}
@@ -0,0 +1,18 @@
+const assert = require("node:assert");
+const { test } = require("node:test");
+
+const Parser = require("tree-sitter");
+
+test("can load grammar", () => {
+  const parser = new Parser();
+  assert.doesNotThrow(() => parser.setLanguage(require(".")));
+});
+
+test("parses a sample", () => {
+  const parser = new Parser();
+  parser.setLanguage(require("."));
+  const tree = parser.parse("fun greet(name) {\n    return name\n}\n");
+  assert.equal(tree.rootNode.type, "source_file");
+  assert.equal(tree.rootNode.firstNamedChild.type, "function_declaration");
+  assert.equal(tree.rootNode.hasError, false);
+});
@@ -4,22 +4,40 @@
 const PREC = {
   assignment: 1,
   conditional: 2,
-  logical_or: 3,
-  logical_and: 4,
-  equality: 5,
-  relational: 6,
-  range: 7,
-  additive: 8,
-  multiplicative: 9,
-  unary: 10,
-  call: 11,
-  member: 12,
+  coalesce: 3,
+  logical_or: 4,
+  logical_and: 5,
+  range: 6,
+  bitwise_or: 7,
+  bitwise_xor: 8,
+  bitwise_and: 9,
+  equality: 10,
+  relational: 11,
+  shift: 12,
+  additive: 13,
+  multiplicative: 14,
+  unary: 15,
+  exponent: 16,
+  call: 17,
+  member: 18,
 };
 
+const DECIMAL_DIGITS = /[0-9]+(_[0-9]+)*/;
+const EXPONENT = /[eE][+-]?[0-9]+(_[0-9]+)*/;
+
 module.exports = grammar({
   name: "patchwork",
 
-  extras: ($) => [/[ \t\u00A0\f]/, $.comment],
+  // Newlines are only significant where the scanner emits a
+  // _statement_terminator; everywhere else (inside parentheses, brackets, and
+  // argument lists) they are skipped like any other whitespace.
+  extras: ($) => [
+    /[ \t\r\n\u00A0\f]/,
+    $.comment,
+    $.block_comment,
+    $.doc_comment,
+    $.heredoc_body,
+  ],
 
   externals: ($) => [
     $.prompt_start,
@@ -30,6 +48,27 @@ module.exports = grammar({
     $.prompt_interpolation_end,
     $.prompt_do,
     $._statement_terminator,
+    // A _statement_terminator followed by a declaration keyword in the first
+    // column, scanned only where the parser does not accept one. That is inside
+    // blocks, so a block left open before the next top-level declaration gets a
+    // MISSING "}" there.
+    $._declaration_terminator,
+    $.string_content,
+    $.interpolation_start,
+    $.interpolation_end,
+    $.block_comment,
+    $.heredoc_start,
+    $._heredoc_body_start,
+    $.heredoc_content,
+    $.heredoc_end,
+    $.doc_comment,
+    $.regex_pattern,
+    // Produced for a block comment still open at the end of the file. No rule
+    // accepts it, so the parser reports it as a single ERROR.
+    $._unterminated_comment,
+    // Produced for number literals that the integer and float tokens reject,
+    // such as `1_` or `0x`.
+    $._invalid_number,
   ],
 
   conflicts: ($) => [
@@ -39,21 +78,60 @@ module.exports = grammar({
     [$.object_literal, $._statement_separator],
     [$.block, $.object_literal],
     [$.expression, $.object_field],
+    [$.expression, $.parameter],
+    [$.string, $.interpolated_string],
+    [$.expression, $.pattern],
+    [$.array_literal, $.array_pattern],
+    [$.object_literal, $.object_pattern],
+    [$.object_field, $.object_pattern],
+    [$.expression, $.rest_pattern],
+    [$.array_literal, $.computed_key],
+    [$.parameter_list, $.tuple_literal],
+    [$.statement, $.expression],
+    [$.lambda_expression, $.expression],
+    [$.match_arm, $.expression],
+    [$.expression, $._expression_member],
+    [$.expression, $.type_expression],
+    [$.while_statement, $.for_statement, $.object_key],
+    [$.while_statement, $.for_statement, $.pair_pattern, $.object_key],
+    [$._expression_or_spread, $.computed_key],
+    [$.block, $.object_pattern, $.object_literal],
+    [$.expression, $.object_pattern, $.object_field],
+    [$.constructor_pattern, $._expression_member],
+    [$._expression_member, $.generic_type],
+    [$.expression, $._expression_member, $.generic_type],
+    [$.tuple_pattern, $.tuple_literal],
+    [$.tuple_literal, $.tuple_type],
+    [$.tuple_pattern, $.tuple_literal, $.tuple_type],
+    [$.pattern, $._destructuring],
+    [$.expression, $.pattern, $.parameter],
+    [$.expression, $.pattern, $.type_expression],
+    [$.expression, $.parameter, $.type_expression],
+    [$.expression, $.pattern, $.parameter, $.type_expression],
   ],
 
-  supertypes: ($) => [$.statement, $.expression, $.type_expression],
+  supertypes: ($) => [
+    $.statement,
+    $.expression,
+    $.type_expression,
+    $.pattern,
+  ],
 
   inline: ($) => [$._declaration],
 
+  // Keywords are matched by lexing an identifier and then checking it against
+  // the keyword list, so `matcher` or `format` is never split at a keyword.
+  word: ($) => $.identifier,
+
   rules: {
     source_file: ($) =>
       seq(
-        optional($._statement_separator),
+        optional($._item_separator),
         optional(
           seq(
             $._item,
-            repeat(seq($._statement_separator, $._item)),
-            optional($._statement_separator),
+            repeat(seq($._item_separator, $._item)),
+            optional($._item_separator),
           ),
         ),
       ),
@@ -61,9 +139,7 @@ module.exports = grammar({
     _item: ($) =>
       seq(
         repeat($.annotation),
-        optional("export"),
-        optional("default"),
-        choice($._declaration, $.statement),
+        choice($._declaration, $.export_statement, $.statement),
       ),
 
     _declaration: ($) =>
@@ -74,6 +150,7 @@ module.exports = grammar({
         $.task_declaration,
         $.function_declaration,
         $.type_declaration,
+        $.enum_declaration,
         $.import_statement,
       ),
 
@@ -82,17 +159,77 @@ module.exports = grammar({
         seq(
           "@",
           field("name", $.identifier),
-          optional(field("argument", choice($.identifier, $.string))),
+          optional(
+            choice(
+              field("argument", choice($.identifier, $.string)),
+              field("arguments", $.argument_list),
+            ),
+          ),
         ),
       ),
 
     import_statement: ($) =>
       seq(
         "import",
-        field("clause", choice($.identifier, $.destructuring_pattern)),
+        field("clause", choice($.identifier, $.import_list, $.namespace_import)),
         optional(seq("from", field("source", choice($.string, $.identifier)))),
       ),
 
+    import_list: ($) =>
+      seq(
+        "{",
+        optional(seq(commaSep1($.import_specifier), optional(","))),
+        "}",
+      ),
+
+    import_specifier: ($) =>
+      seq(
+        field("name", $.identifier),
+        optional(seq("as", field("alias", $.identifier))),
+      ),
+
+    namespace_import: ($) => seq("*", "as", field("alias", $.identifier)),
+
+    export_statement: ($) =>
+      seq(
+        "export",
+        choice(
+          seq(
+            optional("default"),
+            field("declaration", $._exportable_declaration),
+          ),
+          seq(
+            field("clause", $.export_list),
+            optional(seq("from", field("source", $.string))),
+          ),
+        ),
+      ),
+
+    // Imports and tasks cannot be exported.
+    _exportable_declaration: ($) =>
+      choice(
+        $.function_declaration,
+        $.enum_declaration,
+        $.skill_declaration,
+        $.worker_declaration,
+        $.trait_declaration,
+        $.type_declaration,
+        $.var_declaration,
+      ),
+
+    export_list: ($) =>
+      seq(
+        "{",
+        optional(seq(commaSep1($.export_specifier), optional(","))),
+        "}",
+      ),
+
+    export_specifier: ($) =>
+      seq(
+        field("name", $.identifier),
+        optional(seq("as", field("alias", $.identifier))),
+      ),
+
     worker_declaration: ($) =>
       seq(
         "worker",
@@ -120,12 +257,12 @@ module.exports = grammar({
     trait_body: ($) =>
       seq(
         "{",
-        optional($._statement_separator),
+        optional($._item_separator),
         optional(
           seq(
             $._trait_member,
-            repeat(seq($._statement_separator, $._trait_member)),
-            optional($._statement_separator),
+            repeat(seq($._item_separator, $._trait_member)),
+            optional($._item_separator),
           ),
         ),
         "}",
@@ -134,7 +271,17 @@ module.exports = grammar({
     _trait_member: ($) =>
       seq(
         repeat(seq($.annotation, optional($._statement_separator))),
-        $.function_declaration,
+        choice($.function_declaration, $.method_signature),
+      ),
+
+    // A trait method without a body, which implementors must provide.
+    method_signature: ($) =>
+      seq(
+        "fun",
+        field("name", $.identifier),
+        optional(field("type_parameters", $.type_parameters)),
+        field("parameters", $.parameter_list),
+        optional(seq(":", field("return_type", $.type_expression))),
       ),
 
     task_declaration: ($) =>
@@ -147,8 +294,10 @@ module.exports = grammar({
 
     function_declaration: ($) =>
       seq(
+        optional("async"),
         "fun",
         field("name", $.identifier),
+        optional(field("type_parameters", $.type_parameters)),
         field("parameters", optional($.parameter_list)),
         optional(seq(":", field("return_type", $.type_expression))),
         field("body", $.block),
@@ -158,10 +307,45 @@ module.exports = grammar({
       seq(
         "type",
         field("name", $.identifier),
+        optional(field("type_parameters", $.type_parameters)),
         "=",
         field("value", $.type_expression),
       ),
 
+    enum_declaration: ($) =>
+      seq(
+        "enum",
+        field("name", $.identifier),
+        optional(field("type_parameters", $.type_parameters)),
+        field("body", $.enum_body),
+      ),
+
+    enum_body: ($) =>
+      seq("{", optional(seq(commaSep1($.enum_variant), optional(","))), "}"),
+
+    enum_variant: ($) =>
+      seq(
+        field("name", $.identifier),
+        optional(
+          choice(
+            field("payload", choice($.enum_tuple_payload, $.enum_record_payload)),
+            seq("=", field("value", $.expression)),
+          ),
+        ),
+      ),
+
+    enum_tuple_payload: ($) =>
+      seq("(", commaSep1($.type_expression), optional(","), ")"),
+
+    enum_record_payload: ($) =>
+      seq("{", commaSep1($.enum_field), optional(","), "}"),
+
+    enum_field: ($) =>
+      seq(
+        field("name", $.identifier),
+        optional(seq(":", field("type", $.type_expression))),
+      ),
+
     statement: ($) =>
       choice(
         $.block,
@@ -193,36 +377,78 @@ module.exports = grammar({
     var_declaration: ($) =>
       seq(
         "var",
-        field("name", $.identifier),
+        choice(field("name", $.identifier), field("pattern", $._destructuring)),
         optional(seq(":", field("type", $.type_expression))),
         optional(seq("=", field("value", $.expression))),
       ),
 
     return_statement: ($) => seq("return", optional($.expression)),
 
-    break_statement: ($) => seq(choice("break", "succeed", "fail")),
+    break_statement: ($) =>
+      choice(
+        seq("break", optional(field("label", $.identifier))),
+        "succeed",
+        "fail",
+      ),
 
-    continue_statement: ($) => seq("continue"),
+    continue_statement: ($) =>
+      seq("continue", optional(field("label", $.identifier))),
 
     if_statement: ($) =>
       seq(
         "if",
-        field("condition", $.expression),
+        field("condition", $._condition),
         field("consequence", $.block),
         optional(field("alternative", $.else_clause)),
       ),
 
+    _condition: ($) => choice($.expression, $.var_condition, $.condition_chain),
+
+    // `if var Some(x) = lookup(key) { ... }` runs the block only when the value
+    // matches the pattern. The value stops at `&&`, which chains further
+    // conditions instead: `if var x = a && b` tests `b` after binding `a` to
+    // `x`. Operators that bind more loosely than `&&`, such as `||` and `??`,
+    // also end the value, so write `if var x = (a ?? b)` to match against
+    // their result.
+    var_condition: ($) =>
+      prec.left(
+        PREC.logical_and,
+        seq(
+          "var",
+          field("pattern", $.pattern),
+          "=",
+          field("value", $.expression),
+        ),
+      ),
+
+    // A chain of `&&` conditions with at least one var_condition in it; a chain
+    // of plain expressions is an ordinary binary_expression.
+    condition_chain: ($) =>
+      prec.left(
+        PREC.logical_and,
+        choice(
+          seq(
+            field("left", choice($.var_condition, $.condition_chain)),
+            "&&",
+            field("right", choice($.var_condition, $.expression)),
+          ),
+          seq(field("left", $.expression), "&&", field("right", $.var_condition)),
+        ),
+      ),
+
     else_clause: ($) => seq("else", choice($.block, $.if_statement)),
 
     while_statement: ($) =>
       seq(
+        optional(seq(field("label", $.identifier), ":")),
         "while",
-        field("condition", $.expression),
+        field("condition", $._condition),
         field("body", $.block),
       ),
 
     for_statement: ($) =>
       seq(
+        optional(seq(field("label", $.identifier), ":")),
         "for",
         choice(
           seq(
@@ -274,24 +500,142 @@ module.exports = grammar({
     expression: ($) =>
       choice(
         $.assignment_expression,
+        $.augmented_assignment_expression,
+        $.lambda_expression,
+        $.ternary_expression,
         $.binary_expression,
+        $.range_expression,
         $.unary_expression,
         $.await_expression,
+        $.match_expression,
         $.call_expression,
         $.member_expression,
+        $.subscript_expression,
         $.shell_command_expression,
         $.exit_status,
         $.prompt_block,
         $.parenthesized_expression,
         $.array_literal,
+        $.tuple_literal,
+        // Blocks are expressions too, but a leading brace prefers a block
+        // statement (dynamic precedence 0) at the start of a statement and an
+        // object_literal (-1) anywhere else; this alternative is the fallback
+        // for braces that can only be a block.
+        prec.dynamic(-2, $.block),
         $.object_literal,
         $.identifier,
-        $.number,
+        $.integer,
+        $.float,
         $.string,
+        $.char_literal,
+        $.interpolated_string,
+        $.regex,
+        $.heredoc,
         $.boolean,
         $.self_expression,
+        // `await` with no operand is an ordinary variable, since the keyword
+        // is only reserved in front of an expression.
+        prec(-1, alias("await", $.identifier)),
+      ),
+
+    match_expression: ($) =>
+      seq(
+        "match",
+        field("value", $.expression),
+        field("body", $.match_block),
+      ),
+
+    match_block: ($) =>
+      seq(
+        "{",
+        optional($._statement_terminator),
+        optional(
+          seq(
+            $.match_arm,
+            repeat(seq($._match_arm_separator, $.match_arm)),
+            optional($._match_arm_separator),
+          ),
+        ),
+        "}",
+      ),
+
+    match_arm: ($) =>
+      seq(
+        field("pattern", $.pattern),
+        optional(seq("if", field("guard", $.expression))),
+        "=>",
+        field("body", choice($.block, $.expression)),
+      ),
+
+    _match_arm_separator: ($) =>
+      choice(seq(",", optional($._statement_terminator)), $._statement_terminator),
+
+    pattern: ($) =>
+      choice(
+        $.wildcard_pattern,
+        $.identifier,
+        $.integer,
+        $.float,
+        $.string,
+        $.char_literal,
+        $.boolean,
+        $.tuple_pattern,
+        $.constructor_pattern,
+        $.array_pattern,
+        $.object_pattern,
+      ),
+
+    wildcard_pattern: (_) => "_",
+
+    tuple_pattern: ($) =>
+      seq("(", optional(seq(commaSep1($.pattern), optional(","))), ")"),
+
+    constructor_pattern: ($) =>
+      seq(
+        field("constructor", $.identifier),
+        "(",
+        optional(seq(commaSep1(field("argument", $.pattern)), optional(","))),
+        ")",
+      ),
+
+    _destructuring: ($) => choice($.array_pattern, $.object_pattern),
+
+    array_pattern: ($) =>
+      seq(
+        "[",
+        optional(
+          seq(commaSep1(choice($.pattern, $.rest_pattern)), optional(",")),
+        ),
+        "]",
       ),
 
+    object_pattern: ($) =>
+      seq(
+        "{",
+        optional(
+          seq(
+            commaSep1(
+              choice(
+                $.identifier,
+                $.pair_pattern,
+                $.assignment_pattern,
+                $.rest_pattern,
+              ),
+            ),
+            optional(","),
+          ),
+        ),
+        "}",
+      ),
+
+    pair_pattern: ($) =>
+      seq(field("key", $.identifier), ":", field("value", $.pattern)),
+
+    assignment_pattern: ($) =>
+      seq(field("left", $.identifier), "=", field("right", $.expression)),
+
+    rest_pattern: ($) => seq("...", $.identifier),
+
     shell_command_expression: ($) =>
       seq("$(", field("command", $.shell_inner_text), ")"),
 
@@ -299,25 +643,111 @@ module.exports = grammar({
 
     shell_inner_text: (_) => token.immediate(/[^)]+/),
 
-    await_expression: ($) => seq("await", $.expression),
+    // Prefix `await` binds like a unary operator, so member access and calls
+    // are part of the awaited operand: `await foo().bar` is
+    // `await (foo().bar)`, while `await a + b` is `(await a) + b`.
+    await_expression: ($) => prec.right(PREC.unary, seq("await", $.expression)),
 
     assignment_expression: ($) =>
       prec.right(
         PREC.assignment,
         seq(
-          field("left", choice($.identifier, $.member_expression, $.exit_status)),
+          field(
+            "left",
+            choice(
+              $.identifier,
+              $.member_expression,
+              $.subscript_expression,
+              $.exit_status,
+            ),
+          ),
           "=",
           field("right", $.expression),
         ),
       ),
 
+    // Each operator is a single token, so `+=` is never lexed as `+` then `=`.
+    augmented_assignment_expression: ($) =>
+      prec.right(
+        PREC.assignment,
+        seq(
+          field(
+            "left",
+            choice($.identifier, $.member_expression, $.subscript_expression),
+          ),
+          field(
+            "operator",
+            choice("+=", "-=", "*=", "/=", "%=", "&&=", "||=", "??="),
+          ),
+          field("right", $.expression),
+        ),
+      ),
+
+    // The precedence only starts at the arrow. Before it, a bare parameter is
+    // just an identifier that forks the parse, so the `=>` that closes a match
+    // guard like `n if n > max => n` is not taken for a lambda.
+    lambda_expression: ($) =>
+      seq(
+        optional("async"),
+        choice(
+          field("parameters", $.identifier),
+          seq(
+            field("parameters", $.parameter_list),
+            optional(seq(":", field("return_type", $.type_expression))),
+          ),
+        ),
+        prec.right(
+          PREC.assignment,
+          seq("=>", field("body", choice($.block, $.expression))),
+        ),
+      ),
+
+    ternary_expression: ($) =>
+      prec.right(
+        PREC.conditional,
+        seq(
+          field("condition", $.expression),
+          "?",
+          field("consequence", $.expression),
+          ":",
+          field("alternative", $.expression),
+        ),
+      ),
+
+    // `...` is the range operator of the original parser. It needs both
+    // endpoints, so a leading `...` is always a spread_element.
+    range_expression: ($) =>
+      prec.left(
+        PREC.range,
+        seq(
+          field("start", $.expression),
+          field("operator", choice("..", "..=", "...")),
+          field("end", $.expression),
+        ),
+      ),
+
+    // `items[..n]`, `items[n..]` and `items[..]` leave out an endpoint, which
+    // only makes sense when slicing.
+    _open_range: ($) => {
+      const operator = field("operator", choice("..", "..="));
+      return choice(
+        seq(field("start", $.expression), operator),
+        seq(operator, field("end", $.expression)),
+        operator,
+      );
+    },
+
     binary_expression: ($) => {
       const table = [
+        [PREC.coalesce, "??"],
         [PREC.logical_or, "||"],
         [PREC.logical_and, "&&"],
+        [PREC.bitwise_or, "|"],
+        [PREC.bitwise_xor, "^"],
+        [PREC.bitwise_and, "&"],
         [PREC.equality, choice("==", "!=")],
         [PREC.relational, choice("<", "<=", ">", ">=")],
-        [PREC.range, "..."],
+        [PREC.shift, choice("<<", ">>")],
         [PREC.additive, choice("+", "-")],
         [PREC.multiplicative, choice("*", "/", "%")],
       ];
@@ -333,6 +763,14 @@ module.exports = grammar({
             ),
           ),
         ),
+        prec.right(
+          PREC.exponent,
+          seq(
+            field("left", $.expression),
+            field("operator", "**"),
+            field("right", $.expression),
+          ),
+        ),
       );
     },
 
@@ -347,6 +785,8 @@ module.exports = grammar({
         PREC.call,
         seq(
           field("function", $._expression_member),
+          optional(field("optional", $.optional_chain)),
+          optional(field("type_arguments", $.type_arguments)),
           field("arguments", $.argument_list),
         ),
       ),
@@ -356,46 +796,98 @@ module.exports = grammar({
         PREC.member,
         seq(
           field("object", $._expression_member),
-          ".",
+          choice(".", field("optional", $.optional_chain)),
           field("property", $.identifier),
         ),
       ),
 
+    // The index may be a range, as in `items[1..3]` or `items[..n]`.
+    subscript_expression: ($) =>
+      prec(
+        PREC.member,
+        seq(
+          field("object", $._expression_member),
+          optional(field("optional", $.optional_chain)),
+          "[",
+          field(
+            "index",
+            choice($.expression, alias($._open_range, $.range_expression)),
+          ),
+          "]",
+        ),
+      ),
+
+    // `?.` short-circuits the member access, call, or subscript it precedes.
+    optional_chain: (_) => "?.",
+
     _expression_member: ($) =>
       choice(
         $.identifier,
         $.call_expression,
         $.member_expression,
+        $.subscript_expression,
         $.parenthesized_expression,
         $.self_expression,
+        // Regexes are usually used through their methods, as in
+        // `/\d+/.test(line)`.
+        $.regex,
       ),
 
-    argument_list: ($) => seq("(", optional(commaSep($.expression)), ")"),
+    // Positional arguments come first, followed by any named ones.
+    argument_list: ($) =>
+      seq(
+        "(",
+        optional(
+          seq(
+            choice(
+              seq(
+                commaSep1($._expression_or_spread),
+                optional(seq(",", commaSep1($.named_argument))),
+              ),
+              commaSep1($.named_argument),
+            ),
+            optional(","),
+          ),
+        ),
+        ")",
+      ),
+
+    _expression_or_spread: ($) => choice($.expression, $.spread_element),
+
+    spread_element: ($) => seq("...", $.expression),
 
-    parameter_list: ($) => seq("(", optional(commaSep($.parameter)), ")"),
+    named_argument: ($) =>
+      seq(field("name", $.identifier), ":", field("value", $.expression)),
+
+    parameter_list: ($) =>
+      seq("(", optional(seq(commaSep1($.parameter), optional(","))), ")"),
 
     parameter: ($) =>
       seq(
         repeat($.annotation),
-        field("name", $.identifier),
+        choice(field("name", $.identifier), field("pattern", $._destructuring)),
         optional(seq(":", field("type", $.type_expression))),
       ),
 
     parenthesized_expression: ($) => seq("(", $.expression, ")"),
 
+    // `()` is the unit value and `(x,)` a one-element tuple; `(x)` without a
+    // comma is a parenthesized_expression.
+    tuple_literal: ($) => tupleOf($.expression),
+
     array_literal: ($) =>
       seq(
         "[",
         optional($._statement_terminator),
         optional(
           seq(
-            $.expression,
+            $._expression_or_spread,
             repeat(
               seq(
                 optional($._statement_terminator),
                 ",",
                 optional($._statement_terminator),
-                $.expression,
+                $._expression_or_spread,
               ),
             ),
             optional($._statement_terminator),
@@ -414,13 +906,13 @@ module.exports = grammar({
           optional($._statement_terminator),
           optional(
             seq(
-              $.object_field,
+              $._object_element,
               repeat(
                 seq(
                   optional($._statement_terminator),
                   ",",
                   optional($._statement_terminator),
-                  $.object_field,
+                  $._object_element,
                 ),
               ),
               optional($._statement_terminator),
@@ -432,21 +924,113 @@ module.exports = grammar({
         ),
       ),
 
+    _object_element: ($) => choice($.object_field, $.spread_element),
+
     object_field: ($) =>
       choice(
         seq(field("key", $.object_key), ":", field("value", $.expression)),
         field("key", $.identifier),
       ),
 
-    object_key: ($) => choice($.identifier, $.string),
+    object_key: ($) =>
+      choice(
+        $.identifier,
+        // Blocks accept local type declarations, so after a `{` that could
+        // open either, `type` is lexed as the keyword.
+        alias("type", $.identifier),
+        $.string,
+        $.computed_key,
+      ),
+
+    computed_key: ($) => seq("[", $.expression, "]"),
+
+    // The text of `<<END` starts on the next line and runs up to a line holding
+    // only `END`. When `<<END` ends its line the text is part of the heredoc
+    // node; otherwise, as when several heredocs open on one line, each text is
+    // a `heredoc_body` extra following that line.
+    heredoc: ($) =>
+      seq(
+        $.heredoc_start,
+        optional(seq(optional($.heredoc_content), $.heredoc_end)),
+      ),
+
+    heredoc_body: ($) =>
+      seq($._heredoc_body_start, optional($.heredoc_content), $.heredoc_end),
 
     prompt_identifier: (_) => token(/[A-Za-z_][A-Za-z0-9_]*/),
 
     boolean: (_) => choice("true", "false"),
 
-    number: (_) => token(/[0-9]+(\.[0-9]+)?/),
+    integer: (_) =>
+      token(
+        choice(
+          /0[xX][0-9a-fA-F]+(_[0-9a-fA-F]+)*/,
+          /0[bB][01]+(_[01]+)*/,
+          /0[oO][0-7]+(_[0-7]+)*/,
+          DECIMAL_DIGITS,
+        ),
+      ),
+
+    float: (_) =>
+      token(
+        choice(
+          seq(DECIMAL_DIGITS, ".", DECIMAL_DIGITS, optional(EXPONENT)),
+          seq(DECIMAL_DIGITS, EXPONENT),
+        ),
+      ),
+
+    string: ($) =>
+      seq('"', repeat(choice($.string_content, $.escape_sequence)), '"'),
+
+    // An unescaped character is lexed together with its quotes, so no token
+    // matches a lone character and whitespace after a stray `'` is still
+    // skipped during error recovery.
+    char_literal: ($) =>
+      choice(
+        token(seq("'", /[^'\\\r\n]/, "'")),
+        seq("'", $.escape_sequence, token.immediate("'")),
+      ),
+
+    escape_sequence: (_) =>
+      token.immediate(
+        seq(
+          "\\",
+          choice(
+            /[nrt0\\'"$]/,
+            /x[0-9a-fA-F]{2}/,
+            /u\{[0-9a-fA-F]{1,6}\}/,
+          ),
+        ),
+      ),
+
+    // `/` only opens a regex where an expression can start; after an operand
+    // the parser expects the division operator instead, so `a / b / c` never
+    // reaches the regex_pattern scanner.
+    regex: ($) =>
+      seq(
+        "/",
+        field("pattern", $.regex_pattern),
+        token.immediate("/"),
+        optional(field("flags", $.regex_flags)),
+      ),
+
+    regex_flags: (_) => token.immediate(/[a-z]+/),
+
+    interpolated_string: ($) =>
+      seq(
+        '"',
+        repeat(choice($.string_content, $.escape_sequence)),
+        $.interpolation,
+        repeat(choice($.string_content, $.escape_sequence, $.interpolation)),
+        '"',
+      ),
 
-    string: (_) => token(seq('"', repeat(choice(/[^"\\]+/, /\\./)), '"')),
+    interpolation: ($) =>
+      seq(
+        alias($.interpolation_start, "${"),
+        field("expression", $.expression),
+        alias($.interpolation_end, "}"),
+      ),
 
     identifier: (_) => /[A-Za-z_][A-Za-z0-9_]*/,
 
@@ -460,29 +1044,62 @@ module.exports = grammar({
         $.member_expression,
         $.generic_type,
         $.function_type,
+        $.tuple_type,
+        $.array_type,
       ),
 
+    tuple_type: ($) => tupleOf($.type_expression),
+
+    array_type: ($) => seq("[", field("element", $.type_expression), "]"),
+
     generic_type: ($) =>
-      seq(field("base", $.identifier), "<", commaSep1($.type_expression), ">"),
+      seq(field("base", $.identifier), field("arguments", $.type_arguments)),
+
+    // Preferred over a comparison chain when both parse, so `f<Int>(x)` is a
+    // generic call rather than `(f < Int) > (x)`.
+    type_arguments: ($) =>
+      prec.dynamic(1, seq("<", commaSep1($.type_expression), ">")),
+
+    type_parameters: ($) =>
+      seq("<", commaSep1($.type_parameter), optional(","), ">"),
+
+    type_parameter: ($) =>
+      seq(
+        field("name", $.identifier),
+        optional(seq(":", field("bound", $.type_expression))),
+      ),
 
     function_type: ($) =>
       seq("fun", $.parameter_list, optional(seq("->", $.type_expression))),
 
-    destructuring_pattern: ($) =>
-      seq("{", commaSep1($.identifier), optional(","), "}"),
-
-    _annotated_statement: ($) => seq(repeat($.annotation), $.statement),
+    // Blocks also accept local type declarations.
+    _annotated_statement: ($) =>
+      seq(repeat($.annotation), choice($.statement, $.type_declaration)),
 
     _statement_separator: ($) =>
       seq(
         choice(";", $._statement_terminator),
         repeat(choice(";", $._statement_terminator)),
       ),
+
+    _item_separator: ($) =>
+      seq(
+        choice(";", $._statement_terminator, $._declaration_terminator),
+        repeat(choice(";", $._statement_terminator, $._declaration_terminator)),
+      ),
   },
 });
 
+function tupleOf(rule) {
+  return seq(
+    "(",
+    optional(seq(rule, ",", optional(seq(commaSep1(rule), optional(","))))),
+    ")",
+  );
+}
+
 function commaSep(rule) {
-  return seq(rule, repeat(seq(",", rule)));
+  return optional(commaSep1(rule));
 }
 
 function commaSep1(rule) {
@@ -1,18 +1,38 @@
+; Constructs are captured whether or not they span lines; Zed and Neovim only
+; fold the captures that do.
+
 ; Fold blocks in declarations and statements
 [
   (worker_declaration)
   (trait_declaration)
+  (trait_body)
   (task_declaration)
   (skill_declaration)
   (function_declaration)
+  (type_declaration)
+  (enum_declaration)
+  (enum_body)
+  (import_statement)
   (block)
   (prompt_block)
+  (prompt_do_block)
   (if_statement)
   (else_clause)
   (while_statement)
   (for_statement)
+  (match_block)
   (array_literal)
   (object_literal)
   (parameter_list)
   (argument_list)
 ] @fold
+
+; Multi-line comments
+(block_comment) @fold
+(doc_comment) @fold
+
+; Heredoc text
+[
+  (heredoc)
+  (heredoc_body)
+] @fold
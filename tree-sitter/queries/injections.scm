@@ -17,7 +17,25 @@
   command: (shell_inner_text) @injection.content)
  (#set! injection.language "bash"))
 
+; Inject SQL into the first string argument of sql(...) calls, without the
+; surrounding quotes
+((call_expression
+  function: (identifier) @_function
+  arguments: (argument_list
+    .
+    (string) @injection.content))
+ (#eq? @_function "sql")
+ (#offset! @injection.content 0 1 0 -1)
+ (#set! injection.language "sql"))
+
+; Inject the regex grammar into regex literal patterns
+((regex_pattern) @injection.content
+ (#set! injection.language "regex"))
+
 ; Comments can contain markdown-style documentation
 ((comment) @injection.content
- (#match? @injection.content "^#[#!]")
+ (#match? @injection.content "^#!")
+ (#set! injection.language "markdown"))
+
+((doc_comment) @injection.content
  (#set! injection.language "markdown"))
@@ -18,6 +18,16 @@
 (function_declaration
   name: (identifier) @name) @item
 
+(method_signature
+  name: (identifier) @name) @item
+
 ; Type declarations appear in outline
 (type_declaration
   name: (identifier) @name) @item
+
+; Enums and their variants appear in outline
+(enum_declaration
+  name: (identifier) @name) @item
+
+(enum_variant
+  name: (identifier) @name) @item
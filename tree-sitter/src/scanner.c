@@ -1,8 +1,11 @@
+// Only the libc subset available to `tree-sitter build --wasm` is used here:
+// allocation goes through tree_sitter/alloc.h and character classes are
+// checked by hand rather than through <wctype.h>.
 #include <stdbool.h>
 #include <stdint.h>
-#include <stdlib.h>
+#include <string.h>
+#include <tree_sitter/alloc.h>
 #include <tree_sitter/parser.h>
-#include <wctype.h>
 
 #define DEBUG_SCANNER 0
 
@@ -14,6 +17,8 @@
 #endif
 
 #define PROMPT_STACK_CAPACITY 64
+#define HEREDOC_DELIMITER_CAPACITY 32
+#define HEREDOC_QUEUE_CAPACITY 8
 
 enum TokenType {
   PROMPT_START,
@@ -24,19 +29,51 @@ enum TokenType {
   PROMPT_INTERPOLATION_END,
   PROMPT_DO,
   STATEMENT_TERMINATOR,
+  DECLARATION_TERMINATOR,
+  STRING_CONTENT,
+  INTERPOLATION_START,
+  INTERPOLATION_END,
+  BLOCK_COMMENT,
+  HEREDOC_START,
+  HEREDOC_BODY_START,
+  HEREDOC_CONTENT,
+  HEREDOC_END,
+  DOC_COMMENT,
+  REGEX_PATTERN,
+  UNTERMINATED_COMMENT,
+  INVALID_NUMBER,
 };
 
+typedef struct {
+  uint8_t length;
+  char bytes[HEREDOC_DELIMITER_CAPACITY];
+} HeredocDelimiter;
+
 typedef struct {
   uint16_t prompt_depths[PROMPT_STACK_CAPACITY];
   uint8_t prompt_depth_count;
   uint8_t interpolation_depth;
+  uint8_t string_interpolation_depth;
   bool at_line_start;
+  // Delimiters of the heredocs whose bodies have not ended yet, in the order
+  // their bodies appear.
+  HeredocDelimiter heredocs[HEREDOC_QUEUE_CAPACITY];
+  uint8_t heredoc_count;
+  // The first body follows inside its own heredoc node: `<<END` ended its line
+  // and no other heredoc was waiting.
+  bool heredoc_inline;
+  // The first body has started and its lines are being scanned.
+  bool heredoc_body_open;
 } Scanner;
 
 static inline void reset_state(Scanner *scanner) {
   scanner->prompt_depth_count = 0;
   scanner->interpolation_depth = 0;
+  scanner->string_interpolation_depth = 0;
   scanner->at_line_start = true;
+  scanner->heredoc_count = 0;
+  scanner->heredoc_inline = false;
+  scanner->heredoc_body_open = false;
 }
 
 static inline uint16_t *current_prompt_depth(Scanner *scanner) {
@@ -63,21 +100,87 @@ static inline bool is_whitespace(int32_t c) {
   return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f';
 }
 
+static inline bool is_identifier_start(int32_t c) {
+  return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_';
+}
+
 static inline bool is_identifier_continue(int32_t c) {
-  return iswalnum(c) || c == '_';
+  return is_identifier_start(c) || (c >= '0' && c <= '9');
+}
+
+// Reports whether the lexer is at a keyword that can only start a top-level
+// declaration, followed by what that declaration needs next: a name, or for
+// `import` and `export` a name or list. This leaves a line like `task = x`,
+// where the keyword is an ordinary identifier, alone.
+static bool scan_declaration_keyword(TSLexer *lexer) {
+  static const char *const keywords[] = {
+      "fun", "worker", "skill", "trait", "task", "enum", "import", "export",
+  };
+
+  char word[8];
+  unsigned length = 0;
+  while (is_identifier_continue(lexer->lookahead)) {
+    if (length == sizeof(word)) {
+      return false;
+    }
+    word[length++] = (char)lexer->lookahead;
+    lexer->advance(lexer, false);
+  }
+
+  if (lexer->lookahead != ' ' && lexer->lookahead != '\t') {
+    return false;
+  }
+  while (lexer->lookahead == ' ' || lexer->lookahead == '\t') {
+    lexer->advance(lexer, false);
+  }
+
+  for (unsigned i = 0; i < sizeof(keywords) / sizeof(keywords[0]); i++) {
+    if (strlen(keywords[i]) != length || memcmp(keywords[i], word, length) != 0) {
+      continue;
+    }
+    if (is_identifier_start(lexer->lookahead)) {
+      return true;
+    }
+    if (memcmp(word, "import", length) == 0) {
+      return lexer->lookahead == '{' || lexer->lookahead == '*';
+    }
+    if (memcmp(word, "export", length) == 0) {
+      return lexer->lookahead == '{';
+    }
+    return false;
+  }
+  return false;
 }
 
-static bool scan_statement_terminator(Scanner *scanner, TSLexer *lexer) {
+// Newlines are whitespace except where they end a statement. The scanner is
+// only asked for a terminator when the grammar could accept one. That is never
+// the case right after a binary operator or inside parentheses, and array and
+// object literals absorb the terminators they allow, so a newline in those
+// places never ends the enclosing statement. Everywhere else, including
+// `do` blocks inside prompts, a newline followed by a new line of code
+// separates two statements.
+static bool scan_statement_terminator(
+  Scanner *scanner,
+  TSLexer *lexer,
+  const bool *valid_symbols
+) {
   bool saw_newline = false;
+  unsigned column = 0;
   while (true) {
-    if (lexer->lookahead == '\r') {
+    if (lexer->lookahead == ' ' || lexer->lookahead == '\t' ||
+        lexer->lookahead == '\f') {
+      lexer->advance(lexer, !saw_newline);
+      column++;
+    } else if (lexer->lookahead == '\r') {
       saw_newline = true;
+      column = 0;
       lexer->advance(lexer, false);
       if (lexer->lookahead == '\n') {
         lexer->advance(lexer, false);
       }
     } else if (lexer->lookahead == '\n') {
       saw_newline = true;
+      column = 0;
       lexer->advance(lexer, false);
     } else {
       break;
@@ -88,8 +191,42 @@ static bool scan_statement_terminator(Scanner *scanner, TSLexer *lexer) {
     return false;
   }
 
+  lexer->mark_end(lexer);
   lexer->result_symbol = STATEMENT_TERMINATOR;
-  return true;
+
+  // A line that starts with a token which can only continue an expression
+  // belongs to the previous statement, so no terminator is inserted and the
+  // newline is skipped as whitespace.
+  switch (lexer->lookahead) {
+  case '.':
+  case '=':
+  case '?':
+  case ':':
+  case '&':
+  case '|':
+  case '^':
+  case '+':
+  case '-':
+  case '*':
+  case '<':
+  case '>':
+  case ',':
+    return false;
+  case '/':
+    // `/*` opens a comment rather than continuing a division.
+    lexer->advance(lexer, false);
+    return lexer->lookahead == '*';
+  default:
+    // Blocks never contain these declarations, so one that starts a line
+    // marks the end of any block still open above it. Where the grammar
+    // accepts a _declaration_terminator, a statement terminator means the same
+    // thing and the keyword is not looked at.
+    if (column == 0 && !valid_symbols[DECLARATION_TERMINATOR] &&
+        scan_declaration_keyword(lexer)) {
+      lexer->result_symbol = DECLARATION_TERMINATOR;
+    }
+    return true;
+  }
 }
 
 static bool scan_prompt_start(Scanner *scanner, TSLexer *lexer) {
@@ -344,13 +481,537 @@ static bool scan_prompt_text(Scanner *scanner, TSLexer *lexer) {
   return false;
 }
 
+static inline bool is_hex_digit(int32_t c) {
+  return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') ||
+         (c >= 'A' && c <= 'F');
+}
+
+// Consumes what follows a backslash and reports whether it forms an
+// escape_sequence. Unknown escapes such as \q are left to string_content so
+// that a typo does not break the rest of the string.
+static bool scan_escape_tail(TSLexer *lexer) {
+  switch (lexer->lookahead) {
+  case 'n':
+  case 'r':
+  case 't':
+  case '0':
+  case '\\':
+  case '\'':
+  case '"':
+  case '$':
+    return true;
+  case 'x':
+    lexer->advance(lexer, false);
+    if (!is_hex_digit(lexer->lookahead)) {
+      return false;
+    }
+    lexer->advance(lexer, false);
+    return is_hex_digit(lexer->lookahead);
+  case 'u': {
+    lexer->advance(lexer, false);
+    if (lexer->lookahead != '{') {
+      return false;
+    }
+    lexer->advance(lexer, false);
+    int digits = 0;
+    while (is_hex_digit(lexer->lookahead) && digits < 6) {
+      lexer->advance(lexer, false);
+      digits++;
+    }
+    return digits > 0 && lexer->lookahead == '}';
+  }
+  case 0:
+  case '\n':
+    return false;
+  default:
+    lexer->advance(lexer, false);
+    return false;
+  }
+}
+
+// Both `string` and `interpolated_string` are split by the scanner into
+// string_content runs, escape sequences, and interpolation boundaries. Braces
+// inside an embedded expression are matched by the parser itself:
+// `interpolation_end` is only valid once the expression is complete, so nested
+// object literals never close the interpolation early.
+static bool scan_string_content(Scanner *scanner, TSLexer *lexer) {
+  bool has_content = false;
+
+  for (;;) {
+    lexer->mark_end(lexer);
+    int32_t c = lexer->lookahead;
+
+    if (c == 0 || c == '"') {
+      break;
+    }
+
+    if (c == '\\') {
+      lexer->advance(lexer, false);
+      if (scan_escape_tail(lexer)) {
+        break;
+      }
+      has_content = true;
+      continue;
+    }
+
+    if (c == '$') {
+      lexer->advance(lexer, false);
+      if (lexer->lookahead == '{') {
+        break;
+      }
+      has_content = true;
+      continue;
+    }
+
+    lexer->advance(lexer, false);
+    has_content = true;
+  }
+
+  if (!has_content) {
+    return false;
+  }
+
+  lexer->result_symbol = STRING_CONTENT;
+  return true;
+}
+
+static bool scan_interpolation_start(Scanner *scanner, TSLexer *lexer) {
+  if (lexer->lookahead != '$') {
+    return false;
+  }
+
+  lexer->advance(lexer, false);
+  if (lexer->lookahead != '{') {
+    return false;
+  }
+
+  lexer->advance(lexer, false);
+  lexer->mark_end(lexer);
+  scanner->string_interpolation_depth++;
+  lexer->result_symbol = INTERPOLATION_START;
+  return true;
+}
+
+static bool scan_interpolation_end(Scanner *scanner, TSLexer *lexer) {
+  if (scanner->string_interpolation_depth == 0 || lexer->lookahead != '}') {
+    return false;
+  }
+
+  scanner->string_interpolation_depth--;
+  lexer->advance(lexer, false);
+  lexer->mark_end(lexer);
+  lexer->result_symbol = INTERPOLATION_END;
+  return true;
+}
+
+// `## text` documents the declaration that follows it. Each line is its own
+// doc_comment; a single `#` is left to the grammar's ordinary comment token.
+static bool scan_line_doc_comment(TSLexer *lexer) {
+  lexer->advance(lexer, false);
+  if (lexer->lookahead != '#') {
+    return false;
+  }
+
+  while (lexer->lookahead != 0 && lexer->lookahead != '\n' &&
+         lexer->lookahead != '\r') {
+    lexer->advance(lexer, false);
+  }
+
+  lexer->mark_end(lexer);
+  lexer->result_symbol = DOC_COMMENT;
+  return true;
+}
+
+static inline bool is_digit_in_base(int32_t c, int base) {
+  switch (base) {
+  case 2:
+    return c == '0' || c == '1';
+  case 8:
+    return c >= '0' && c <= '7';
+  case 16:
+    return is_hex_digit(c);
+  default:
+    return c >= '0' && c <= '9';
+  }
+}
+
+// Consumes a run of digits in the given base, allowing a single underscore
+// between two of them. Returns false at an underscore that is not followed by
+// a digit.
+static bool skip_digits(TSLexer *lexer, int base) {
+  while (is_digit_in_base(lexer->lookahead, base) || lexer->lookahead == '_') {
+    if (lexer->lookahead == '_') {
+      lexer->advance(lexer, false);
+      if (!is_digit_in_base(lexer->lookahead, base)) {
+        return false;
+      }
+    }
+    lexer->advance(lexer, false);
+  }
+  return true;
+}
+
+// The integer and float tokens only match well-formed literals, so on their
+// own `1_` would lex as `1` followed by the identifier `_`, and `0x` as `0`
+// followed by `x`. Catch a digit separator that is not followed by a digit, a
+// base prefix without digits, and an exponent without digits here instead,
+// whether in the integer part, the fraction, or the exponent, and turn the
+// whole literal into INVALID_NUMBER, which no rule accepts. Well-formed
+// literals are left to the internal lexer.
+static bool scan_invalid_number(TSLexer *lexer) {
+  int base = 10;
+  bool malformed = false;
+
+  if (lexer->lookahead == '0') {
+    lexer->advance(lexer, false);
+    switch (lexer->lookahead) {
+    case 'x':
+    case 'X':
+      base = 16;
+      break;
+    case 'b':
+    case 'B':
+      base = 2;
+      break;
+    case 'o':
+    case 'O':
+      base = 8;
+      break;
+    }
+    if (base != 10) {
+      lexer->advance(lexer, false);
+      malformed = !is_digit_in_base(lexer->lookahead, base);
+    }
+  }
+
+  if (!malformed) {
+    malformed = !skip_digits(lexer, base);
+  }
+
+  // A dot that is not followed by a digit belongs to a range or a member
+  // access, and leaves a well-formed integer behind.
+  if (!malformed && base == 10 && lexer->lookahead == '.') {
+    lexer->advance(lexer, false);
+    if (!is_digit_in_base(lexer->lookahead, 10)) {
+      return false;
+    }
+    malformed = !skip_digits(lexer, 10);
+  }
+
+  if (!malformed && base == 10 &&
+      (lexer->lookahead == 'e' || lexer->lookahead == 'E')) {
+    lexer->advance(lexer, false);
+    if (lexer->lookahead == '+' || lexer->lookahead == '-') {
+      lexer->advance(lexer, false);
+    }
+    malformed =
+        !is_digit_in_base(lexer->lookahead, 10) || !skip_digits(lexer, 10);
+  }
+
+  if (!malformed) {
+    return false;
+  }
+
+  while (is_identifier_continue(lexer->lookahead)) {
+    lexer->advance(lexer, false);
+  }
+  lexer->mark_end(lexer);
+  lexer->result_symbol = INVALID_NUMBER;
+  return true;
+}
+
+// Block comments nest. One that opens with `/**` is a doc_comment, except for
+// the empty comment `/**/`. A comment that is still open at the end of the
+// file becomes UNTERMINATED_COMMENT, which no rule accepts, so the parser
+// reports it as one ERROR instead of lexing its text as code.
+static bool scan_block_comment(Scanner *scanner, TSLexer *lexer,
+                               const bool *valid_symbols) {
+  if (lexer->lookahead == '#') {
+    return valid_symbols[DOC_COMMENT] && scan_line_doc_comment(lexer);
+  }
+
+  if (lexer->lookahead != '/') {
+    return false;
+  }
+  lexer->advance(lexer, false);
+  if (lexer->lookahead != '*') {
+    return false;
+  }
+  lexer->advance(lexer, false);
+
+  enum TokenType symbol = BLOCK_COMMENT;
+  if (lexer->lookahead == '*') {
+    lexer->advance(lexer, false);
+    if (lexer->lookahead == '/') {
+      lexer->advance(lexer, false);
+      lexer->mark_end(lexer);
+      lexer->result_symbol = BLOCK_COMMENT;
+      return true;
+    }
+    if (valid_symbols[DOC_COMMENT]) {
+      symbol = DOC_COMMENT;
+    }
+  }
+
+  unsigned depth = 1;
+  for (;;) {
+    if (lexer->eof(lexer)) {
+      lexer->mark_end(lexer);
+      lexer->result_symbol = UNTERMINATED_COMMENT;
+      return true;
+    }
+
+    int32_t c = lexer->lookahead;
+
+    lexer->advance(lexer, false);
+    if (c == '*' && lexer->lookahead == '/') {
+      lexer->advance(lexer, false);
+      if (--depth == 0) {
+        lexer->mark_end(lexer);
+        lexer->result_symbol = symbol;
+        return true;
+      }
+    } else if (c == '/' && lexer->lookahead == '*') {
+      lexer->advance(lexer, false);
+      depth++;
+    }
+  }
+}
+
+// The body of a regex literal, after the opening `/`. REGEX_PATTERN is only
+// valid right after a `/` in a position where an expression can start, which
+// is what tells a regex apart from division: the parser state, rather than the
+// scanner, remembers whether the previous token could end an expression. A
+// `/` inside a character class or after a backslash does not close the
+// pattern, and a pattern may not span lines.
+static bool scan_regex_pattern(TSLexer *lexer) {
+  bool in_class = false;
+  bool has_content = false;
+
+  for (;;) {
+    int32_t c = lexer->lookahead;
+    switch (c) {
+    case 0:
+    case '\n':
+    case '\r':
+      return false;
+    case '/':
+      if (!in_class) {
+        if (!has_content) {
+          return false;
+        }
+        lexer->mark_end(lexer);
+        lexer->result_symbol = REGEX_PATTERN;
+        return true;
+      }
+      break;
+    case '[':
+      in_class = true;
+      break;
+    case ']':
+      in_class = false;
+      break;
+    case '\\':
+      lexer->advance(lexer, false);
+      if (lexer->lookahead == 0 || lexer->lookahead == '\n' ||
+          lexer->lookahead == '\r') {
+        return false;
+      }
+      break;
+    }
+    lexer->advance(lexer, false);
+    has_content = true;
+  }
+}
+
+static inline void pop_heredoc(Scanner *scanner) {
+  if (scanner->heredoc_count == 0) {
+    return;
+  }
+  scanner->heredoc_count--;
+  memmove(
+    &scanner->heredocs[0],
+    &scanner->heredocs[1],
+    scanner->heredoc_count * sizeof(HeredocDelimiter)
+  );
+  scanner->heredoc_inline = false;
+  scanner->heredoc_body_open = false;
+}
+
+static inline void skip_blanks(TSLexer *lexer) {
+  while (lexer->lookahead == ' ' || lexer->lookahead == '\t' ||
+         lexer->lookahead == '\f') {
+    lexer->advance(lexer, true);
+  }
+}
+
+// Heredocs work like Ruby's: the body of `<<END` starts on the next line and
+// runs until a line holding only the delimiter. When `<<END` ends its line,
+// heredoc_content and heredoc_end are children of its `heredoc` node. A line
+// may also open several heredocs, as in `compare(<<LEFT, <<RIGHT)`; their
+// delimiters are queued and the bodies follow one after another, each as a
+// `heredoc_body` extra. The queue is part of the serialized state so an edit
+// inside a body can resume scanning.
+static bool scan_heredoc_start(Scanner *scanner, TSLexer *lexer) {
+  skip_blanks(lexer);
+
+  if (lexer->lookahead != '<') {
+    return false;
+  }
+  lexer->advance(lexer, false);
+  if (lexer->lookahead != '<') {
+    return false;
+  }
+  lexer->advance(lexer, false);
+
+  if (!is_identifier_start(lexer->lookahead) ||
+      scanner->heredoc_count >= HEREDOC_QUEUE_CAPACITY) {
+    return false;
+  }
+
+  HeredocDelimiter delimiter = {0};
+  while (is_identifier_continue(lexer->lookahead)) {
+    if (delimiter.length >= HEREDOC_DELIMITER_CAPACITY) {
+      return false;
+    }
+    delimiter.bytes[delimiter.length++] = (char)lexer->lookahead;
+    lexer->advance(lexer, false);
+  }
+  lexer->mark_end(lexer);
+
+  skip_blanks(lexer);
+  bool ends_line = lexer->eof(lexer) || lexer->lookahead == '\n' ||
+                   lexer->lookahead == '\r';
+  scanner->heredoc_inline = scanner->heredoc_count == 0 && ends_line;
+  scanner->heredocs[scanner->heredoc_count++] = delimiter;
+  lexer->result_symbol = HEREDOC_START;
+  return true;
+}
+
+// Skips the rest of the current line, which has to be blank, and its line
+// break.
+static bool skip_to_next_line(TSLexer *lexer, bool skip) {
+  while (lexer->lookahead == ' ' || lexer->lookahead == '\t' ||
+         lexer->lookahead == '\f') {
+    lexer->advance(lexer, skip);
+  }
+  if (lexer->lookahead == '\r') {
+    lexer->advance(lexer, skip);
+  }
+  if (lexer->lookahead != '\n') {
+    return false;
+  }
+  lexer->advance(lexer, skip);
+  return true;
+}
+
+// Starts the body of the first queued heredoc at the line break that ends the
+// line it was opened on.
+static bool scan_heredoc_body_start(Scanner *scanner, TSLexer *lexer) {
+  if (scanner->heredoc_count == 0 || scanner->heredoc_inline ||
+      scanner->heredoc_body_open) {
+    return false;
+  }
+
+  if (!skip_to_next_line(lexer, false)) {
+    return false;
+  }
+  lexer->mark_end(lexer);
+  scanner->heredoc_body_open = true;
+  lexer->result_symbol = HEREDOC_BODY_START;
+  return true;
+}
+
+// Consumes the delimiter if the rest of the line is exactly that delimiter. A
+// line that merely contains the delimiter does not match.
+static bool scan_heredoc_delimiter(Scanner *scanner, TSLexer *lexer) {
+  HeredocDelimiter *delimiter = &scanner->heredocs[0];
+  for (uint8_t i = 0; i < delimiter->length; i++) {
+    if (lexer->lookahead != (int32_t)(uint8_t)delimiter->bytes[i]) {
+      return false;
+    }
+    lexer->advance(lexer, false);
+  }
+
+  return lexer->eof(lexer) || lexer->lookahead == '\n' ||
+         lexer->lookahead == '\r';
+}
+
+static bool scan_heredoc_body(
+  Scanner *scanner,
+  TSLexer *lexer,
+  const bool *valid_symbols
+) {
+  if (scanner->heredoc_count == 0) {
+    return false;
+  }
+
+  // An inline body starts on the line after `<<END`, so that line break is
+  // skipped. A queued body is scanned only once heredoc_body_start has begun
+  // it.
+  if (scanner->heredoc_inline) {
+    if (!skip_to_next_line(lexer, true)) {
+      return false;
+    }
+  } else if (!scanner->heredoc_body_open) {
+    return false;
+  }
+
+  bool has_content = false;
+  for (;;) {
+    lexer->mark_end(lexer);
+    if (lexer->eof(lexer)) {
+      break;
+    }
+
+    // The closing delimiter may be indented.
+    while (lexer->lookahead == ' ' || lexer->lookahead == '\t') {
+      lexer->advance(lexer, false);
+    }
+
+    if (scan_heredoc_delimiter(scanner, lexer)) {
+      if (has_content) {
+        break;
+      }
+      if (!valid_symbols[HEREDOC_END]) {
+        return false;
+      }
+      lexer->mark_end(lexer);
+      pop_heredoc(scanner);
+      lexer->result_symbol = HEREDOC_END;
+      return true;
+    }
+
+    while (!lexer->eof(lexer) && lexer->lookahead != '\n' &&
+           lexer->lookahead != '\r') {
+      lexer->advance(lexer, false);
+    }
+    if (lexer->lookahead == '\r') {
+      lexer->advance(lexer, false);
+    }
+    if (lexer->lookahead == '\n') {
+      lexer->advance(lexer, false);
+    }
+    has_content = true;
+  }
+
+  if (!has_content || !valid_symbols[HEREDOC_CONTENT]) {
+    return false;
+  }
+
+  scanner->heredoc_inline = false;
+  scanner->heredoc_body_open = true;
+  lexer->result_symbol = HEREDOC_CONTENT;
+  return true;
+}
+
 void *tree_sitter_patchwork_external_scanner_create(void) {
-  Scanner *scanner = calloc(1, sizeof(Scanner));
+  Scanner *scanner = ts_calloc(1, sizeof(Scanner));
   return scanner;
 }
 
 void tree_sitter_patchwork_external_scanner_destroy(void *payload) {
-  free(payload);
+  ts_free(payload);
 }
 
 void tree_sitter_patchwork_external_scanner_reset(void *payload) {
@@ -372,12 +1033,22 @@ unsigned tree_sitter_patchwork_external_scanner_serialize(
   unsigned size = 0;
   buffer[size++] = (char)scanner->prompt_depth_count;
   buffer[size++] = (char)scanner->interpolation_depth;
+  buffer[size++] = (char)scanner->string_interpolation_depth;
   buffer[size++] = (char)scanner->at_line_start;
   for (uint8_t i = 0; i < scanner->prompt_depth_count; i++) {
     uint16_t depth = scanner->prompt_depths[i];
     buffer[size++] = (char)(depth & 0xFFu);
     buffer[size++] = (char)((depth >> 8) & 0xFFu);
   }
+  buffer[size++] = (char)scanner->heredoc_inline;
+  buffer[size++] = (char)scanner->heredoc_body_open;
+  buffer[size++] = (char)scanner->heredoc_count;
+  for (uint8_t i = 0; i < scanner->heredoc_count; i++) {
+    HeredocDelimiter *delimiter = &scanner->heredocs[i];
+    buffer[size++] = (char)delimiter->length;
+    memcpy(&buffer[size], delimiter->bytes, delimiter->length);
+    size += delimiter->length;
+  }
   return size;
 }
 
@@ -397,6 +1068,9 @@ void tree_sitter_patchwork_external_scanner_deserialize(
   if (cursor < length) {
     scanner->interpolation_depth = (uint8_t)buffer[cursor++];
   }
+  if (cursor < length) {
+    scanner->string_interpolation_depth = (uint8_t)buffer[cursor++];
+  }
   if (cursor < length) {
     scanner->at_line_start = buffer[cursor++] != 0;
   }
@@ -409,6 +1083,27 @@ void tree_sitter_patchwork_external_scanner_deserialize(
     uint8_t hi = (uint8_t)buffer[cursor++];
     scanner->prompt_depths[i] = (uint16_t)(lo | (hi << 8));
   }
+  if (cursor + 2 >= length) {
+    return;
+  }
+  scanner->heredoc_inline = buffer[cursor++] != 0;
+  scanner->heredoc_body_open = buffer[cursor++] != 0;
+  uint8_t heredoc_count = (uint8_t)buffer[cursor++];
+  for (uint8_t i = 0; i < heredoc_count && i < HEREDOC_QUEUE_CAPACITY; i++) {
+    if (cursor >= length) {
+      break;
+    }
+    uint8_t delimiter_length = (uint8_t)buffer[cursor++];
+    if (delimiter_length > HEREDOC_DELIMITER_CAPACITY ||
+        cursor + delimiter_length > length) {
+      break;
+    }
+    HeredocDelimiter *delimiter = &scanner->heredocs[i];
+    delimiter->length = delimiter_length;
+    memcpy(delimiter->bytes, &buffer[cursor], delimiter_length);
+    cursor += delimiter_length;
+    scanner->heredoc_count = i + 1;
+  }
 }
 
 bool tree_sitter_patchwork_external_scanner_scan(
@@ -434,6 +1129,11 @@ bool tree_sitter_patchwork_external_scanner_scan(
     return true;
   }
 
+  if (valid_symbols[INTERPOLATION_END] &&
+      scan_interpolation_end(scanner, lexer)) {
+    return true;
+  }
+
   if (valid_symbols[PROMPT_INTERPOLATION_START] &&
       scan_prompt_interpolation_start(scanner, lexer)) {
     return true;
@@ -443,8 +1143,27 @@ bool tree_sitter_patchwork_external_scanner_scan(
     return true;
   }
 
+  if (valid_symbols[INTERPOLATION_START] &&
+      scan_interpolation_start(scanner, lexer)) {
+    return true;
+  }
+
+  if (valid_symbols[HEREDOC_START] && scan_heredoc_start(scanner, lexer)) {
+    return true;
+  }
+
+  if (valid_symbols[HEREDOC_BODY_START] &&
+      scan_heredoc_body_start(scanner, lexer)) {
+    return true;
+  }
+
+  if ((valid_symbols[HEREDOC_CONTENT] || valid_symbols[HEREDOC_END]) &&
+      scan_heredoc_body(scanner, lexer, valid_symbols)) {
+    return true;
+  }
+
   if (valid_symbols[STATEMENT_TERMINATOR] &&
-      scan_statement_terminator(scanner, lexer)) {
+      scan_statement_terminator(scanner, lexer, valid_symbols)) {
     return true;
   }
 
@@ -452,8 +1171,44 @@ bool tree_sitter_patchwork_external_scanner_scan(
     return true;
   }
 
-  if (valid_symbols[PROMPT_TEXT]) {
-    return scan_prompt_text(scanner, lexer);
+  if (valid_symbols[PROMPT_TEXT] && scan_prompt_text(scanner, lexer)) {
+    return true;
+  }
+
+  // Every symbol is valid during error recovery. No statement can end inside a
+  // string, so when both are valid this is not string content either.
+  if (valid_symbols[STRING_CONTENT] && !valid_symbols[STATEMENT_TERMINATOR] &&
+      scan_string_content(scanner, lexer)) {
+    return true;
+  }
+
+  // A statement terminator is never valid right after the opening `/` either,
+  // so seeing one means this is not a regex.
+  if (valid_symbols[REGEX_PATTERN] && !valid_symbols[STATEMENT_TERMINATOR] &&
+      scan_regex_pattern(lexer)) {
+    return true;
+  }
+
+  // Comments and numbers can follow any amount of whitespace, including line
+  // breaks that did not end a statement.
+  while (is_whitespace(lexer->lookahead)) {
+    lexer->advance(lexer, true);
+  }
+
+  // A digit inside a string, a heredoc, a regex, or prompt text is just text.
+  // Every symbol is valid during error recovery, and no statement can end
+  // inside any of them, so when a statement terminator is valid too this is
+  // not text after all.
+  bool in_text = (valid_symbols[STRING_CONTENT] ||
+                  valid_symbols[HEREDOC_CONTENT] ||
+                  valid_symbols[REGEX_PATTERN] || valid_symbols[PROMPT_TEXT]) &&
+                 !valid_symbols[STATEMENT_TERMINATOR];
+  if (!in_text && lexer->lookahead >= '0' && lexer->lookahead <= '9') {
+    return scan_invalid_number(lexer);
+  }
+
+  if (valid_symbols[BLOCK_COMMENT] || valid_symbols[DOC_COMMENT]) {
+    return scan_block_comment(scanner, lexer, valid_symbols);
   }
 
   return false;
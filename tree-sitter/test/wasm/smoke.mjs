@@ -0,0 +1,31 @@
+// Loads the compiled tree-sitter-patchwork.wasm with web-tree-sitter and
+// parses a small program, failing if the tree contains errors.
+//
+// Usage: node test/wasm/smoke.mjs [path/to/tree-sitter-patchwork.wasm]
+import { fileURLToPath } from "node:url";
+import path from "node:path";
+import Parser from "web-tree-sitter";
+
+const root = path.resolve(path.dirname(fileURLToPath(import.meta.url)), "../..");
+const wasmPath = process.argv[2] ?? path.join(root, "tree-sitter-patchwork.wasm");
+
+await Parser.init();
+const language = await Parser.Language.load(wasmPath);
+const parser = new Parser();
+parser.setLanguage(language);
+
+const source = `## Greets someone.
+fun greet(name) {
+    var message = "hello \${name}\\n"
+    return message
+}
+`;
+const tree = parser.parse(source);
+const rootNode = tree.rootNode;
+
+if (rootNode.type !== "source_file" || rootNode.hasError()) {
+  console.error(rootNode.toString());
+  process.exit(1);
+}
+
+console.log(rootNode.toString());